@@ -0,0 +1,103 @@
+package opensea
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+)
+
+type AccountProfile struct {
+	Address             Address              `json:"address" bson:"address"`
+	Username            string               `json:"username" bson:"username"`
+	ProfileImageURL     string               `json:"profile_image_url" bson:"profile_image_url"`
+	BannerImageURL      string               `json:"banner_image_url" bson:"banner_image_url"`
+	Website             string               `json:"website" bson:"website"`
+	SocialMediaAccounts []SocialMediaAccount `json:"social_media_accounts" bson:"social_media_accounts"`
+	Bio                 string               `json:"bio" bson:"bio"`
+	JoinedDate          string               `json:"joined_date" bson:"joined_date"`
+}
+
+type SocialMediaAccount struct {
+	Platform string `json:"platform" bson:"platform"`
+	Username string `json:"username" bson:"username"`
+}
+
+func (o Opensea) GetAccount(ctx context.Context, addressOrUsername string) (*AccountProfile, error) {
+	path := "/api/v2/accounts/" + url.PathEscape(addressOrUsername)
+	b, err := o.GetPath(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	ret := new(AccountProfile)
+	return ret, json.Unmarshal(b, ret)
+}
+
+// Portfolio is what a wallet holds, grouped by collection and valued at the collection floors.
+type Portfolio struct {
+	Owner       Address                `json:"owner" bson:"owner"`
+	Collections []*PortfolioCollection `json:"collections" bson:"collections"`
+	Value       Amount                 `json:"value" bson:"value"`
+}
+
+type PortfolioCollection struct {
+	Slug       string      `json:"slug" bson:"slug"`
+	Collection *Collection `json:"collection" bson:"collection"`
+	Assets     []Asset     `json:"assets" bson:"assets"`
+	FloorPrice Amount      `json:"floor_price" bson:"floor_price"` // zero when the collection has no floor
+	Value      Amount      `json:"value" bson:"value"`             // floor price times the number of assets
+}
+
+// Portfolio crawls every asset owned by address and values each collection at its floor price.
+// Collections are sorted by value, most valuable first.
+func (o Opensea) Portfolio(ctx context.Context, address string) (*Portfolio, error) {
+	owner, err := ParseAddress(address)
+	if err != nil {
+		return nil, err
+	}
+	p := &Portfolio{Owner: owner, Value: Amount{Decimals: EtherDecimals}}
+	bySlug := map[string]*PortfolioCollection{}
+
+	params := GetAssetsParams{Owner: owner.String(), Limit: 50}
+	for {
+		resp, err := o.GetAssetsWithContext(ctx, params)
+		if err != nil {
+			return nil, err
+		}
+		for _, a := range resp.Assets {
+			slug := ""
+			if a.Collection != nil {
+				slug = a.Collection.Slug
+			}
+			c, ok := bySlug[slug]
+			if !ok {
+				c = &PortfolioCollection{Slug: slug, Collection: a.Collection}
+				bySlug[slug] = c
+				p.Collections = append(p.Collections, c)
+			}
+			c.Assets = append(c.Assets, a)
+		}
+		if resp.Next == "" || len(resp.Assets) == 0 {
+			break
+		}
+		params.Cursor = resp.Next
+	}
+
+	for _, c := range p.Collections {
+		if c.Slug == "" {
+			continue
+		}
+		stat, err := o.GetCollectionStatsWithContext(ctx, c.Slug)
+		if err != nil {
+			return nil, fmt.Errorf("stats of %s: %w", c.Slug, err)
+		}
+		c.FloorPrice = stat.FloorPrice.Amount
+		c.Value = c.FloorPrice.Mul(int64(len(c.Assets)))
+		p.Value = p.Value.Add(c.Value)
+	}
+	sort.SliceStable(p.Collections, func(i, j int) bool {
+		return p.Collections[i].Value.Cmp(p.Collections[j].Value) > 0
+	})
+	return p, nil
+}
@@ -0,0 +1,65 @@
+package opensea
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetAccount(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v2/accounts/MaFriends", r.URL.Path)
+		w.Write([]byte(`{"address": "0xbc0c08bc6c8e949d54db31eddc8ee809ef323ec3", "username": "MaFriends",
+			"social_media_accounts": [{"platform": "twitter", "username": "mafriendsart"}]}`))
+	}))
+	defer srv.Close()
+
+	client := Opensea{API: srv.URL, httpClient: srv.Client()}
+	ret, err := client.GetAccount(context.Background(), "MaFriends")
+	assert.Nil(t, err)
+	assert.Equal(t, Address("0xbc0c08bc6c8e949d54db31eddc8ee809ef323ec3"), ret.Address)
+	assert.Equal(t, "twitter", ret.SocialMediaAccounts[0].Platform)
+}
+
+func TestPortfolio(t *testing.T) {
+	owner := "0xbc0c08bc6c8e949d54db31eddc8ee809ef323ec3"
+	asset := func(id, slug string) Asset {
+		return Asset{TokenID: id, Collection: &Collection{Slug: slug}}
+	}
+	pages := map[string]AssetResponse{
+		"":   {Next: "p2", Assets: []Asset{asset("1", "doodles"), asset("2", "cheap")}},
+		"p2": {Assets: []Asset{asset("3", "doodles")}},
+	}
+	floors := map[string]float64{"doodles": 2.6, "cheap": 0.01}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/api/v1/assets":
+			assert.Equal(t, owner, r.URL.Query().Get("owner"))
+			json.NewEncoder(w).Encode(pages[r.URL.Query().Get("cursor")])
+		case "/api/v1/collection/doodles/stats", "/api/v1/collection/cheap/stats":
+			slug := r.URL.Path[len("/api/v1/collection/") : len(r.URL.Path)-len("/stats")]
+			fmt.Fprintf(w, `{"stats": {"floor_price": %v}}`, floors[slug])
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	client := Opensea{API: srv.URL, httpClient: srv.Client()}
+	p, err := client.Portfolio(context.Background(), owner)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(p.Collections))
+	assert.Equal(t, "doodles", p.Collections[0].Slug)
+	assert.Equal(t, 2, len(p.Collections[0].Assets))
+	assert.Equal(t, "5200000000000000000", p.Collections[0].Value.String())
+	assert.Equal(t, "5210000000000000000", p.Value.String())
+
+	_, err = client.Portfolio(context.Background(), "not an address")
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,315 @@
+package opensea
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+)
+
+// EtherDecimals is the number of decimals of ETH and WETH, assumed for amounts until their payment token is known.
+const EtherDecimals = 18
+
+// Amount is an exact amount of a payment token, kept as an integer of its smallest unit (wei for ETH)
+// together with the token decimals. The zero value is zero wei.
+//
+// In JSON an Amount is the integer of base units, as a string or a number.
+type Amount struct {
+	Int      *big.Int
+	Decimals int
+}
+
+func NewAmount(base *big.Int, decimals int) Amount {
+	return Amount{Int: new(big.Int).Set(base), Decimals: decimals}
+}
+
+// ParseAmount parses an integer of base units. OpenSea sometimes appends a fraction of zeros
+// ("214503000000000000.0000000000"), which is accepted; any other fraction is an error.
+func ParseAmount(s string, decimals int) (Amount, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Amount{Decimals: decimals}, nil
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return Amount{}, errors.New("Invalid amount: " + s)
+	}
+	if !r.IsInt() {
+		return Amount{}, errors.New("Invalid amount, fraction of base unit: " + s)
+	}
+	return Amount{Int: new(big.Int).Set(r.Num()), Decimals: decimals}, nil
+}
+
+// ParseUnits parses a human readable amount such as "2.6" ETH into base units.
+func ParseUnits(s string, decimals int) (Amount, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Amount{Decimals: decimals}, nil
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return Amount{}, errors.New("Invalid amount: " + s)
+	}
+	r.Mul(r, new(big.Rat).SetInt(pow10(decimals)))
+	if !r.IsInt() {
+		return Amount{}, fmt.Errorf("Invalid amount, more than %d decimals: %s", decimals, s)
+	}
+	return Amount{Int: new(big.Int).Set(r.Num()), Decimals: decimals}, nil
+}
+
+// Ether returns an amount of ETH from its human readable form, panicking on malformed input.
+func Ether(s string) Amount {
+	a, err := ParseUnits(s, EtherDecimals)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// Big returns a copy of the base units.
+func (a Amount) Big() *big.Int {
+	if a.Int == nil {
+		return new(big.Int)
+	}
+	return new(big.Int).Set(a.Int)
+}
+
+// String returns the base units.
+func (a Amount) String() string {
+	return a.Big().String()
+}
+
+// Units returns the exact human readable amount, without trailing zeros.
+func (a Amount) Units() string {
+	n := a.Big()
+	if a.Decimals <= 0 {
+		return n.String()
+	}
+	neg := n.Sign() < 0
+	s := n.Abs(n).String()
+	if len(s) <= a.Decimals {
+		s = strings.Repeat("0", a.Decimals-len(s)+1) + s
+	}
+	whole, frac := s[:len(s)-a.Decimals], strings.TrimRight(s[len(s)-a.Decimals:], "0")
+	if frac != "" {
+		whole += "." + frac
+	}
+	if neg {
+		whole = "-" + whole
+	}
+	return whole
+}
+
+// Float64 is the nearest float to the human readable amount, for display and statistics only.
+func (a Amount) Float64() float64 {
+	f, _ := new(big.Rat).SetFrac(a.Big(), pow10(a.Decimals)).Float64()
+	return f
+}
+
+// WithDecimals returns the same base units labelled with other decimals.
+func (a Amount) WithDecimals(decimals int) Amount {
+	return Amount{Int: a.Big(), Decimals: decimals}
+}
+
+// Rescale converts the amount to other decimals, keeping its value; it truncates when losing precision.
+func (a Amount) Rescale(decimals int) Amount {
+	n := a.Big()
+	switch {
+	case decimals > a.Decimals:
+		n.Mul(n, pow10(decimals-a.Decimals))
+	case decimals < a.Decimals:
+		n.Quo(n, pow10(a.Decimals-decimals))
+	}
+	return Amount{Int: n, Decimals: decimals}
+}
+
+func (a Amount) IsZero() bool {
+	return a.Int == nil || a.Int.Sign() == 0
+}
+
+func (a Amount) Sign() int {
+	if a.Int == nil {
+		return 0
+	}
+	return a.Int.Sign()
+}
+
+// Add, Sub and Cmp work on the value, bringing both amounts to the larger decimals first.
+func (a Amount) Add(b Amount) Amount {
+	x, y := align(a, b)
+	return Amount{Int: x.Int.Add(x.Int, y.Int), Decimals: x.Decimals}
+}
+
+func (a Amount) Sub(b Amount) Amount {
+	x, y := align(a, b)
+	return Amount{Int: x.Int.Sub(x.Int, y.Int), Decimals: x.Decimals}
+}
+
+func (a Amount) Cmp(b Amount) int {
+	x, y := align(a, b)
+	return x.Int.Cmp(y.Int)
+}
+
+func (a Amount) Mul(n int64) Amount {
+	return Amount{Int: new(big.Int).Mul(a.Big(), big.NewInt(n)), Decimals: a.Decimals}
+}
+
+// MulBasisPoints returns bp/10000 of the amount, truncated to the base unit.
+func (a Amount) MulBasisPoints(bp int64) Amount {
+	n := new(big.Int).Mul(a.Big(), big.NewInt(bp))
+	return Amount{Int: n.Quo(n, big.NewInt(10000)), Decimals: a.Decimals}
+}
+
+func (a *Amount) UnmarshalJSON(b []byte) error {
+	decimals := a.Decimals
+	if decimals == 0 {
+		decimals = EtherDecimals
+	}
+	b = bytes.TrimSpace(b)
+	if string(b) == "null" {
+		*a = Amount{Decimals: decimals}
+		return nil
+	}
+	s := string(b)
+	if len(b) > 0 && b[0] == '"' {
+		var err error
+		if s, err = strconv.Unquote(s); err != nil {
+			return err
+		}
+	}
+	v, err := ParseAmount(s, decimals)
+	if err != nil {
+		return err
+	}
+	*a = v
+	return nil
+}
+
+func (a Amount) MarshalJSON() ([]byte, error) {
+	return []byte(strconv.Quote(a.String())), nil
+}
+
+// MarshalBSONValue stores the amount as a Decimal128 with as many fraction digits as the token has decimals,
+// 0.5 ETH being 0.500000000000000000, so that both survive the round trip. An amount of more than 34 digits
+// is stored as a string of base units and decimals, such as 500000000000000000E-18.
+func (a Amount) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	if a.Int == nil {
+		return bsontype.Null, nil, nil
+	}
+	if new(big.Int).Abs(a.Int).Cmp(maxDecimal128) <= 0 {
+		if d, ok := primitive.ParseDecimal128FromBigInt(a.Int, -a.Decimals); ok {
+			return bson.MarshalValue(d)
+		}
+	}
+	return bson.MarshalValue(a.Int.String() + "E-" + strconv.Itoa(a.Decimals))
+}
+
+// UnmarshalBSONValue reads what MarshalBSONValue writes; plain integers are base units of EtherDecimals.
+func (a *Amount) UnmarshalBSONValue(typ bsontype.Type, b []byte) error {
+	v := bson.RawValue{Type: typ, Value: b}
+	switch typ {
+	case bsontype.Null, bsontype.Undefined:
+		*a = Amount{Decimals: EtherDecimals}
+	case bsontype.Decimal128:
+		n, exp, err := v.Decimal128().BigInt()
+		if err != nil {
+			return err
+		}
+		if exp > 0 {
+			n.Mul(n, pow10(exp))
+			exp = 0
+		}
+		*a = Amount{Int: n, Decimals: -exp}
+	case bsontype.String:
+		s := v.StringValue()
+		decimals := EtherDecimals
+		if i := strings.Index(s, "E-"); i >= 0 {
+			d, err := strconv.Atoi(s[i+2:])
+			if err != nil {
+				return errors.New("Invalid amount: " + s)
+			}
+			s, decimals = s[:i], d
+		}
+		amount, err := ParseAmount(s, decimals)
+		if err != nil {
+			return err
+		}
+		*a = amount
+	case bsontype.Int32, bsontype.Int64:
+		i, _ := v.AsInt64OK()
+		*a = Amount{Int: big.NewInt(i), Decimals: EtherDecimals}
+	default:
+		return errors.New("Invalid amount: cannot decode BSON " + typ.String())
+	}
+	return nil
+}
+
+// EtherAmount is an Amount of ETH that OpenSea writes in ether rather than wei, as in the collection stats.
+type EtherAmount struct {
+	Amount
+}
+
+func (e *EtherAmount) UnmarshalJSON(b []byte) error {
+	b = bytes.TrimSpace(b)
+	if string(b) == "null" {
+		*e = EtherAmount{Amount{Decimals: EtherDecimals}}
+		return nil
+	}
+	s := string(b)
+	if len(b) > 0 && b[0] == '"' {
+		var err error
+		if s, err = strconv.Unquote(s); err != nil {
+			return err
+		}
+	}
+	v, err := roundUnits(s, EtherDecimals)
+	if err != nil {
+		return err
+	}
+	*e = EtherAmount{v}
+	return nil
+}
+
+// roundUnits is ParseUnits for values such as the floats of the collection stats, which may carry
+// more digits than the token has decimals; those are rounded to the nearest base unit, halves away from zero.
+func roundUnits(s string, decimals int) (Amount, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Amount{Decimals: decimals}, nil
+	}
+	r, ok := new(big.Rat).SetString(s)
+	if !ok {
+		return Amount{}, errors.New("Invalid amount: " + s)
+	}
+	r.Mul(r, new(big.Rat).SetInt(pow10(decimals)))
+	q, m := new(big.Int).QuoRem(r.Num(), r.Denom(), new(big.Int))
+	if m.Abs(m).Lsh(m, 1).Cmp(r.Denom()) >= 0 {
+		q.Add(q, big.NewInt(int64(r.Sign())))
+	}
+	return Amount{Int: q, Decimals: decimals}, nil
+}
+
+func (e EtherAmount) MarshalJSON() ([]byte, error) {
+	return []byte(e.Rescale(EtherDecimals).Units()), nil
+}
+
+// maxDecimal128 is the largest coefficient a Decimal128 holds, 34 nines.
+var maxDecimal128, _ = new(big.Int).SetString(strings.Repeat("9", 34), 10)
+
+func align(a, b Amount) (Amount, Amount) {
+	d := a.Decimals
+	if b.Decimals > d {
+		d = b.Decimals
+	}
+	return a.Rescale(d), b.Rescale(d)
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
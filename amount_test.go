@@ -0,0 +1,151 @@
+package opensea
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+)
+
+func TestParseAmount(t *testing.T) {
+	a, err := ParseAmount("214503000000000000.0000000000", EtherDecimals)
+	assert.Nil(t, err)
+	assert.Equal(t, "214503000000000000", a.String())
+	assert.Equal(t, "0.214503", a.Units())
+
+	_, err = ParseAmount("1.5", EtherDecimals)
+	assert.NotNil(t, err)
+	_, err = ParseAmount("abc", EtherDecimals)
+	assert.NotNil(t, err)
+
+	usdc, err := ParseUnits("1234.56", 6)
+	assert.Nil(t, err)
+	assert.Equal(t, "1234560000", usdc.String())
+	_, err = ParseUnits("0.0000001", 6)
+	assert.NotNil(t, err)
+
+	small, err := ParseUnits("1e-05", EtherDecimals)
+	assert.Nil(t, err)
+	assert.Equal(t, "0.00001", small.Units())
+}
+
+func TestNumberBig(t *testing.T) {
+	n, err := Number("250").Big()
+	assert.Nil(t, err)
+	assert.Equal(t, "250", n.String())
+	n, err = Number("214503000000000000.0000000000").Big()
+	assert.Nil(t, err)
+	assert.Equal(t, "214503000000000000", n.String())
+	n, err = Number("").Big()
+	assert.Nil(t, err)
+	assert.Nil(t, n)
+
+	_, err = Number("1.5").Big()
+	assert.EqualError(t, err, "Invalid amount, fraction of base unit: 1.5")
+	_, err = Number("12abc").Big()
+	assert.EqualError(t, err, "Invalid amount: 12abc")
+	assert.Equal(t, int64(0), Number("1.5").Int64())
+}
+
+func TestAmountArithmetic(t *testing.T) {
+	a := Ether("0.1")
+	b := Ether("0.2")
+	assert.Equal(t, "0.3", a.Add(b).Units())
+	assert.Equal(t, "-0.1", a.Sub(b).Units())
+	assert.Equal(t, -1, a.Cmp(b))
+	assert.Equal(t, 0, a.Add(a).Cmp(b))
+	assert.Equal(t, "0.025", Ether("1").MulBasisPoints(250).Units())
+	assert.Equal(t, "0.3", a.Mul(3).Units())
+
+	// values compare across decimals
+	usdc, _ := ParseUnits("1", 6)
+	assert.Equal(t, 0, usdc.Cmp(Ether("1")))
+	assert.Equal(t, "2", usdc.Add(Ether("1")).Units())
+
+	var zero Amount
+	assert.True(t, zero.IsZero())
+	assert.Equal(t, "0", zero.Units())
+	assert.Equal(t, 1, b.Cmp(zero))
+}
+
+func TestAmountJSON(t *testing.T) {
+	var v struct {
+		A Amount      `json:"a"`
+		B Amount      `json:"b"`
+		C Amount      `json:"c"`
+		E EtherAmount `json:"e"`
+		F EtherAmount `json:"f"`
+	}
+	err := json.Unmarshal([]byte(`{"a": "1000000000000000000", "b": 25000000000000000, "c": null, "e": 2.6, "f": "37255.917015196734"}`), &v)
+	assert.Nil(t, err)
+	assert.Equal(t, "1", v.A.Units())
+	assert.Equal(t, "0.025", v.B.Units())
+	assert.True(t, v.C.IsZero())
+	assert.Equal(t, "2600000000000000000", v.E.String())
+	assert.Equal(t, "37255.917015196734", v.F.Units())
+
+	by, err := json.Marshal(v)
+	assert.Nil(t, err)
+	assert.JSONEq(t, `{"a": "1000000000000000000", "b": "25000000000000000", "c": "0", "e": 2.6, "f": 37255.917015196734}`, string(by))
+
+	assert.NotNil(t, json.Unmarshal([]byte(`"0.5"`), &v.A))
+
+	// stats floats with more digits than wei are rounded to the nearest wei
+	var stat Stat
+	err = json.Unmarshal([]byte(`{"floor_price": 0.0012345678901234567, "average_price": 0.0012345678901234564, "total_volume": 1.5e-19, "market_cap": -0.0000000000000000015}`), &stat)
+	assert.Nil(t, err)
+	assert.Equal(t, "1234567890123457", stat.FloorPrice.String())
+	assert.Equal(t, "1234567890123456", stat.AveragePrice.String())
+	assert.Equal(t, "0", stat.TotalVolume.String())
+	assert.Equal(t, "-2", stat.MarketCap.String())
+	assert.NotNil(t, json.Unmarshal([]byte(`"0.1x"`), &v.E))
+}
+
+func TestAmountFixtures(t *testing.T) {
+	b, err := ioutil.ReadFile("test-files/opensea-stats-doodles.json")
+	assert.Nil(t, err)
+	var stats StatResponse
+	assert.Nil(t, json.Unmarshal(b, &stats))
+	assert.Equal(t, 1, stats.Stats.FloorPrice.Sign())
+
+	b, err = ioutil.ReadFile("test-files/listings-v2.json")
+	assert.Nil(t, err)
+	var ord OrderV2
+	assert.Nil(t, json.Unmarshal(b, &ord))
+	assert.Equal(t, "1", ord.CurrentPrice.Units())
+
+	str := `{"event_type": "successful", "total_price": "1234560000", "payment_token": {"symbol": "USDC", "decimals": 6}}`
+	var e Event
+	assert.Nil(t, json.Unmarshal([]byte(str), &e))
+	assert.Equal(t, "1234.56", e.TotalPrice.Units())
+}
+
+func TestAmountBSON(t *testing.T) {
+	usdc, _ := ParseUnits("12.5", 6)
+	huge, _ := ParseAmount("115792089237316195423570985008687907853269984665640564039457584007913129639935", 18)
+	doc := struct {
+		Price EtherAmount `bson:"price"`
+		Usdc  Amount      `bson:"usdc"`
+		Huge  Amount      `bson:"huge"`
+		Unset Amount      `bson:"unset"`
+	}{Price: EtherAmount{Ether("0.5")}, Usdc: usdc, Huge: huge}
+
+	raw, err := bson.Marshal(doc)
+	assert.Nil(t, err)
+	r := bson.Raw(raw)
+	assert.Equal(t, bsontype.Decimal128, r.Lookup("price").Type)
+	assert.Equal(t, "0.500000000000000000", r.Lookup("price").Decimal128().String())
+	assert.Equal(t, bsontype.String, r.Lookup("huge").Type)
+	assert.Equal(t, bsontype.Null, r.Lookup("unset").Type)
+
+	doc.Price, doc.Usdc, doc.Huge = EtherAmount{}, Amount{}, Amount{}
+	assert.Nil(t, bson.Unmarshal(raw, &doc))
+	assert.Equal(t, 0, doc.Price.Cmp(Ether("0.5")))
+	assert.Equal(t, 18, doc.Price.Decimals)
+	assert.Equal(t, usdc, doc.Usdc)
+	assert.Equal(t, huge, doc.Huge)
+	assert.Nil(t, doc.Unset.Int)
+}
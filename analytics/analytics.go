@@ -0,0 +1,114 @@
+// Package analytics turns successful events into the time series dashboards need: OHLC candles,
+// volume, volume weighted average prices and counts of sales, buyers and sellers, per collection.
+// WashFilter leaves out the sales that look like wash trades; its zero fee check needs the fees of
+// the sales, which events do not carry, from the caller.
+//
+// All prices are in ETH, valued at the payment token prices of each sale.
+package analytics
+
+import (
+	"context"
+	"math/big"
+	"sort"
+	"strconv"
+	"time"
+
+	"github.com/jumpblock/go-opensea"
+)
+
+// Sale is a successful event reduced to what the analytics use. Price is the total paid for Quantity
+// tokens and Fee the fees the trade paid out of it, both in ETH. Fee is nil when unknown, which it is for
+// every sale of FromEvents as events do not report the fees of a trade; callers that know them, from
+// the transaction for instance, set it.
+type Sale struct {
+	EventID    uint64
+	Collection string
+	Contract   string
+	TokenID    string
+	Seller     string
+	Buyer      string
+	Time       time.Time
+	Quantity   int64
+	Price      opensea.Amount
+	Fee        *opensea.Amount
+}
+
+// UnitPrice is the price of one token of the sale.
+func (s Sale) UnitPrice() opensea.Amount {
+	if s.Quantity <= 1 {
+		return s.Price
+	}
+	return opensea.Amount{Int: new(big.Int).Quo(s.Price.Big(), big.NewInt(s.Quantity)), Decimals: s.Price.Decimals}
+}
+
+// FromEvents values the successful events with n, which uses the prices OpenSea attached to the
+// events when nil, and returns them oldest first. Sales in a token without an ETH price are left out.
+func FromEvents(ctx context.Context, events []*opensea.Event, n *opensea.PriceNormalizer) ([]Sale, error) {
+	if n == nil {
+		n = opensea.NewPriceNormalizer(nil)
+	}
+	var ret []Sale
+	for _, e := range events {
+		if e.EventType != opensea.EventTypeSuccessful {
+			continue
+		}
+		price, err := n.EventPrice(ctx, e)
+		if err != nil {
+			return nil, err
+		}
+		if price.Eth == nil {
+			continue
+		}
+		s := Sale{
+			EventID:    e.ID,
+			Collection: e.CollectionSlug,
+			Seller:     account(e.Seller),
+			Buyer:      account(e.WinnerAccount),
+			Time:       e.OccurredAt(),
+			Quantity:   1,
+			Price:      *price.Eth,
+		}
+		if q, err := strconv.ParseInt(e.Quantity, 10, 64); err == nil && q > 0 {
+			s.Quantity = q
+		}
+		var collection *opensea.Collection
+		if a := e.Asset; a != nil {
+			s.TokenID, collection = a.TokenID, a.Collection
+			if a.AssetContract != nil {
+				s.Contract = a.AssetContract.Address.String()
+			}
+		} else if b := e.AssetBundle; b != nil {
+			collection = b.Collection
+			if b.AssetContract != nil {
+				s.Contract = b.AssetContract.Address.String()
+			}
+		}
+		if s.Collection == "" && collection != nil {
+			s.Collection = collection.Slug
+		}
+		ret = append(ret, s)
+	}
+	sort.SliceStable(ret, func(i, j int) bool {
+		if !ret[i].Time.Equal(ret[j].Time) {
+			return ret[i].Time.Before(ret[j].Time)
+		}
+		return ret[i].EventID < ret[j].EventID
+	})
+	return ret, nil
+}
+
+// ByCollection groups sales by collection slug, keeping their order.
+func ByCollection(sales []Sale) map[string][]Sale {
+	ret := map[string][]Sale{}
+	for _, s := range sales {
+		ret[s.Collection] = append(ret[s.Collection], s)
+	}
+	return ret
+}
+
+func account(a *opensea.Account) string {
+	if a == nil {
+		return ""
+	}
+	return a.Address.String()
+}
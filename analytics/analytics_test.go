@@ -0,0 +1,72 @@
+package analytics
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+	"time"
+
+	"github.com/jumpblock/go-opensea"
+	"github.com/stretchr/testify/assert"
+)
+
+func fixtureEvents(t *testing.T) []*opensea.Event {
+	b, err := ioutil.ReadFile("../test-files/opensea-events.json")
+	assert.Nil(t, err)
+	var resp opensea.AssetEventsResponse
+	assert.Nil(t, json.Unmarshal(b, &resp))
+	return resp.AssetEvents
+}
+
+func TestFromEvents(t *testing.T) {
+	events := fixtureEvents(t)
+	events = append(events, &opensea.Event{ID: 1, EventType: opensea.EventTypeTransfer})
+	sales, err := FromEvents(context.Background(), events, nil)
+	assert.Nil(t, err)
+	assert.Len(t, sales, 8)
+
+	first := sales[0]
+	assert.Equal(t, uint64(651665248), first.EventID)
+	assert.Equal(t, "forgotten-runes-mafriends", first.Collection)
+	assert.Equal(t, "0x495f947276749ce646f68ac8c248420045cb7b5e", first.Contract)
+	assert.Equal(t, "0xbc0c08bc6c8e949d54db31eddc8ee809ef323ec3", first.Seller)
+	assert.Equal(t, "0x45aef14112f92bdc3fa08ca0d85c8a96af47c490", first.Buyer)
+	assert.Equal(t, time.Date(2021, 8, 26, 22, 48, 39, 936150000, time.UTC), first.Time.UTC())
+	assert.Equal(t, int64(1), first.Quantity)
+	assert.Equal(t, "0.2", first.Price.Units())
+	assert.Nil(t, first.Fee, "events do not report the fees of a trade")
+
+	for i := 1; i < len(sales); i++ {
+		assert.False(t, sales[i].Time.Before(sales[i-1].Time))
+	}
+	assert.True(t, sales[7].Price.IsZero())
+}
+
+func TestFromEventsSkipsUnpricedTokens(t *testing.T) {
+	e := &opensea.Event{
+		ID:           1,
+		EventType:    opensea.EventTypeSuccessful,
+		TotalPrice:   opensea.Ether("5"),
+		PaymentToken: &opensea.PaymentToken{Symbol: "APE", Address: "0x4d224452801aced8b2f0aebe155379bb5d594381", Decimals: 18},
+	}
+	sales, err := FromEvents(context.Background(), []*opensea.Event{e}, nil)
+	assert.Nil(t, err)
+	assert.Empty(t, sales)
+
+	oracle, err := opensea.NewStaticOracle(map[string][2]string{"0x4d224452801aced8b2f0aebe155379bb5d594381": {"0.002", "3.50"}})
+	assert.Nil(t, err)
+	sales, err = FromEvents(context.Background(), []*opensea.Event{e}, opensea.NewPriceNormalizer(oracle))
+	assert.Nil(t, err)
+	if assert.Len(t, sales, 1) {
+		assert.Equal(t, "0.01", sales[0].Price.Units())
+		assert.Nil(t, sales[0].Fee)
+	}
+}
+
+func TestUnitPrice(t *testing.T) {
+	s := Sale{Price: opensea.Ether("1"), Quantity: 3}
+	assert.Equal(t, "0.333333333333333333", s.UnitPrice().Units())
+	s.Quantity = 0
+	assert.Equal(t, "1", s.UnitPrice().Units())
+}
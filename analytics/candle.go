@@ -0,0 +1,138 @@
+package analytics
+
+import (
+	"math/big"
+	"sort"
+	"time"
+
+	"github.com/jumpblock/go-opensea"
+)
+
+// Stats are the figures of a set of sales. Open, High, Low and Close are unit prices, VWAP is the
+// volume over the quantity sold.
+type Stats struct {
+	Open     opensea.Amount
+	High     opensea.Amount
+	Low      opensea.Amount
+	Close    opensea.Amount
+	Volume   opensea.Amount
+	VWAP     opensea.Amount
+	Sales    int
+	Quantity int64
+	Buyers   int // unique wallets
+	Sellers  int
+}
+
+// Candle is the Stats of the sales in [Start, Start+interval).
+type Candle struct {
+	Start time.Time
+	Stats
+}
+
+// Summary is the Stats of all the sales of a collection, between the First and Last of them.
+type Summary struct {
+	Collection string
+	First      time.Time
+	Last       time.Time
+	Stats
+}
+
+// Candles buckets sales into intervals aligned as by time.Truncate, so that days start at midnight UTC,
+// from the interval of the first sale to that of the last. An interval without sales repeats the
+// previous close, with no volume.
+func Candles(sales []Sale, interval time.Duration) []Candle {
+	if len(sales) == 0 || interval <= 0 {
+		return nil
+	}
+	sales = sorted(sales)
+	var ret []Candle
+	var acc accumulator
+	start := sales[0].Time.UTC().Truncate(interval)
+	for _, s := range sales {
+		for !s.Time.Before(start.Add(interval)) {
+			ret = append(ret, Candle{Start: start, Stats: acc.stats()})
+			acc = accumulator{prevClose: acc.close()}
+			start = start.Add(interval)
+		}
+		acc.add(s)
+	}
+	return append(ret, Candle{Start: start, Stats: acc.stats()})
+}
+
+// Summarize returns the Summary of each collection, by slug.
+func Summarize(sales []Sale) map[string]Summary {
+	ret := map[string]Summary{}
+	for slug, ss := range ByCollection(sorted(sales)) {
+		var acc accumulator
+		for _, s := range ss {
+			acc.add(s)
+		}
+		ret[slug] = Summary{Collection: slug, First: ss[0].Time, Last: ss[len(ss)-1].Time, Stats: acc.stats()}
+	}
+	return ret
+}
+
+func sorted(sales []Sale) []Sale {
+	if sort.SliceIsSorted(sales, func(i, j int) bool { return sales[i].Time.Before(sales[j].Time) }) {
+		return sales
+	}
+	sales = append([]Sale{}, sales...)
+	sort.SliceStable(sales, func(i, j int) bool { return sales[i].Time.Before(sales[j].Time) })
+	return sales
+}
+
+type accumulator struct {
+	Stats
+	prevClose *opensea.Amount
+	buyers    map[string]bool
+	sellers   map[string]bool
+}
+
+func (a *accumulator) add(s Sale) {
+	p := s.UnitPrice()
+	if a.Sales == 0 {
+		a.Open, a.High, a.Low = p, p, p
+		a.Volume = opensea.Amount{Int: new(big.Int), Decimals: p.Decimals}
+		a.buyers, a.sellers = map[string]bool{}, map[string]bool{}
+	}
+	if p.Cmp(a.High) > 0 {
+		a.High = p
+	}
+	if p.Cmp(a.Low) < 0 {
+		a.Low = p
+	}
+	a.Close = p
+	a.Volume = a.Volume.Add(s.Price)
+	a.Sales++
+	a.Quantity += s.Quantity
+	if s.Buyer != "" {
+		a.buyers[s.Buyer] = true
+	}
+	if s.Seller != "" {
+		a.sellers[s.Seller] = true
+	}
+}
+
+// close is the last price seen, in this or an earlier interval.
+func (a *accumulator) close() *opensea.Amount {
+	if a.Sales > 0 {
+		c := a.Close
+		return &c
+	}
+	return a.prevClose
+}
+
+func (a *accumulator) stats() Stats {
+	st := a.Stats
+	if st.Sales == 0 {
+		if a.prevClose != nil {
+			st.Open, st.High, st.Low, st.Close = *a.prevClose, *a.prevClose, *a.prevClose, *a.prevClose
+		}
+		return st
+	}
+	st.Buyers, st.Sellers = len(a.buyers), len(a.sellers)
+	if st.Quantity > 0 {
+		st.VWAP = opensea.Amount{Int: new(big.Int).Quo(st.Volume.Big(), big.NewInt(st.Quantity)), Decimals: st.Volume.Decimals}
+	}
+	return st
+}
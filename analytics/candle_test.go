@@ -0,0 +1,88 @@
+package analytics
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jumpblock/go-opensea"
+	"github.com/stretchr/testify/assert"
+)
+
+var day = time.Date(2022, 6, 1, 0, 0, 0, 0, time.UTC)
+
+func sale(hours int, price string, seller, buyer string) Sale {
+	return Sale{
+		Collection: "doodles-official",
+		Contract:   "0x8a90cab2b38dba80c64b7734e58ee1db38b8992e",
+		TokenID:    "1",
+		Seller:     seller,
+		Buyer:      buyer,
+		Time:       day.Add(time.Duration(hours) * time.Hour),
+		Quantity:   1,
+		Price:      opensea.Ether(price),
+	}
+}
+
+func TestCandles(t *testing.T) {
+	sales := []Sale{
+		sale(1, "2", "a", "b"),
+		sale(3, "3", "b", "c"),
+		sale(2, "1", "c", "a"),
+		sale(72, "4", "a", "d"),
+	}
+	sales[3].Quantity, sales[3].Price = 2, opensea.Ether("8")
+
+	candles := Candles(sales, 24*time.Hour)
+	assert.Len(t, candles, 4)
+
+	c := candles[0]
+	assert.Equal(t, day, c.Start)
+	assert.Equal(t, "2", c.Open.Units())
+	assert.Equal(t, "3", c.High.Units())
+	assert.Equal(t, "1", c.Low.Units())
+	assert.Equal(t, "3", c.Close.Units())
+	assert.Equal(t, "6", c.Volume.Units())
+	assert.Equal(t, "2", c.VWAP.Units())
+	assert.Equal(t, 3, c.Sales)
+	assert.Equal(t, int64(3), c.Quantity)
+	assert.Equal(t, 3, c.Buyers)
+	assert.Equal(t, 3, c.Sellers)
+
+	for _, gap := range candles[1:3] {
+		assert.Equal(t, 0, gap.Sales)
+		assert.Equal(t, "3", gap.Open.Units())
+		assert.Equal(t, "3", gap.Close.Units())
+		assert.True(t, gap.Volume.IsZero())
+	}
+	assert.Equal(t, day.Add(48*time.Hour), candles[2].Start)
+
+	c = candles[3]
+	assert.Equal(t, day.Add(72*time.Hour), c.Start)
+	assert.Equal(t, "4", c.Open.Units(), "unit price of 2 tokens for 8")
+	assert.Equal(t, "8", c.Volume.Units())
+	assert.Equal(t, "4", c.VWAP.Units())
+
+	hourly := Candles(sales[:3], time.Hour)
+	assert.Len(t, hourly, 3)
+	assert.Equal(t, "1", hourly[1].Close.Units())
+
+	assert.Nil(t, Candles(nil, time.Hour))
+}
+
+func TestSummarize(t *testing.T) {
+	sales := []Sale{sale(1, "2", "a", "b"), sale(5, "4", "b", "a"), sale(3, "1", "c", "d")}
+	sales[2].Collection = "cryptopunks"
+
+	sums := Summarize(sales)
+	assert.Len(t, sums, 2)
+	s := sums["doodles-official"]
+	assert.Equal(t, 2, s.Sales)
+	assert.Equal(t, "6", s.Volume.Units())
+	assert.Equal(t, "3", s.VWAP.Units())
+	assert.Equal(t, "2", s.Low.Units())
+	assert.Equal(t, "4", s.High.Units())
+	assert.Equal(t, 2, s.Buyers)
+	assert.Equal(t, day.Add(time.Hour), s.First)
+	assert.Equal(t, day.Add(5*time.Hour), s.Last)
+	assert.Equal(t, 1, sums["cryptopunks"].Sales)
+}
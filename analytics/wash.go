@@ -0,0 +1,74 @@
+package analytics
+
+import "time"
+
+// WashReason tells why a sale looks like a wash trade.
+type WashReason string
+
+const (
+	WashSelfTrade WashReason = "self_trade" // the buyer is the seller
+	WashRoundTrip WashReason = "round_trip" // the token went back to the seller
+	WashZeroFee   WashReason = "zero_fee"   // no fee was paid
+)
+
+// WashFilter flags the sales that look like wash trades; each check is off when zero.
+type WashFilter struct {
+	SelfTrades bool
+	// RoundTrip flags both sales when the buyer of a token sells it back to its seller within it.
+	RoundTrip time.Duration
+	// ZeroFee flags the sales whose Fee is set and zero. FromEvents leaves Fee nil, so it only applies
+	// to fees the caller fills in; the dev_fee_payment_event of an event is a periodic payout to the
+	// collection, not the fee of that trade.
+	ZeroFee bool
+}
+
+// Flag returns the reason each sale looks like a wash trade, "" for those that do not, in the order
+// of sales.
+func (f WashFilter) Flag(sales []Sale) []WashReason {
+	ret := make([]WashReason, len(sales))
+	// earlier sales of each token, by contract and token ID
+	byToken := map[string][]int{}
+	for i, s := range sales {
+		switch {
+		case f.SelfTrades && s.Seller != "" && s.Seller == s.Buyer:
+			ret[i] = WashSelfTrade
+		case f.ZeroFee && s.Fee != nil && s.Fee.IsZero():
+			ret[i] = WashZeroFee
+		}
+		if f.RoundTrip <= 0 || s.TokenID == "" || s.Seller == "" || s.Buyer == "" {
+			continue
+		}
+		key := s.Contract + "/" + s.TokenID
+		for _, j := range byToken[key] {
+			p := sales[j]
+			if p.Seller == s.Buyer && p.Buyer == s.Seller && absDuration(s.Time.Sub(p.Time)) <= f.RoundTrip {
+				if ret[i] == "" {
+					ret[i] = WashRoundTrip
+				}
+				if ret[j] == "" {
+					ret[j] = WashRoundTrip
+				}
+			}
+		}
+		byToken[key] = append(byToken[key], i)
+	}
+	return ret
+}
+
+// Filter returns the sales that do not look like wash trades, in order.
+func (f WashFilter) Filter(sales []Sale) []Sale {
+	var ret []Sale
+	for i, reason := range f.Flag(sales) {
+		if reason == "" {
+			ret = append(ret, sales[i])
+		}
+	}
+	return ret
+}
+
+func absDuration(d time.Duration) time.Duration {
+	if d < 0 {
+		return -d
+	}
+	return d
+}
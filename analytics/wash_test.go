@@ -0,0 +1,55 @@
+package analytics
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jumpblock/go-opensea"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWashFilter(t *testing.T) {
+	zero := opensea.Ether("0")
+	sales := []Sale{
+		sale(0, "1", "a", "b"),
+		sale(1, "1", "c", "c"),
+		sale(24, "1", "b", "a"),
+		sale(30, "1", "d", "e"),
+		sale(30*24, "1", "e", "d"),
+		sale(31*24, "0", "f", "g"),
+	}
+	sales[5].Fee = &zero
+
+	f := WashFilter{SelfTrades: true, RoundTrip: 7 * 24 * time.Hour, ZeroFee: true}
+	assert.Equal(t, []WashReason{WashRoundTrip, WashSelfTrade, WashRoundTrip, "", "", WashZeroFee}, f.Flag(sales))
+	kept := f.Filter(sales)
+	if assert.Len(t, kept, 2) {
+		assert.Equal(t, "d", kept[0].Seller)
+		assert.Equal(t, "e", kept[1].Seller)
+	}
+
+	// Round trips of other tokens do not count.
+	sales[2].TokenID = "2"
+	assert.Equal(t, []WashReason{"", WashSelfTrade, "", "", "", WashZeroFee}, f.Flag(sales))
+
+	assert.Len(t, WashFilter{}.Filter(sales), len(sales))
+
+	// Sales between unknown accounts are no round trips.
+	unknown := []Sale{sale(0, "1", "", ""), sale(1, "1", "", "")}
+	assert.Equal(t, []WashReason{"", ""}, f.Flag(unknown))
+}
+
+func TestWashFilterEvents(t *testing.T) {
+	sales, err := FromEvents(context.Background(), fixtureEvents(t), nil)
+	assert.Nil(t, err)
+	// Fees are unknown, so that none is flagged for them, not even in a zero-fee collection.
+	assert.Len(t, WashFilter{ZeroFee: true}.Filter(sales), len(sales))
+
+	zero := opensea.Ether("0")
+	sales[0].Fee = &zero
+	kept := WashFilter{ZeroFee: true}.Filter(sales)
+	if assert.Len(t, kept, len(sales)-1) {
+		assert.NotEqual(t, sales[0].EventID, kept[0].EventID)
+	}
+}
@@ -0,0 +1,166 @@
+package opensea
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
+)
+
+const (
+	// MaxTokenIDsPerQuery is the most token_ids the v2 listings endpoint accepts in one query.
+	MaxTokenIDsPerQuery = 30
+	defaultConcurrency  = 4
+)
+
+// BatchOptions tunes GetActiveListingsBatch; zero values take the defaults.
+type BatchOptions struct {
+	ChunkSize   int // token ids per query, at most MaxTokenIDsPerQuery
+	Concurrency int // queries in flight at once
+}
+
+// TokenListings are the active listings of one token, or the error that prevented fetching them.
+type TokenListings struct {
+	TokenID string
+	Orders  []*OrderV2
+	Err     error
+}
+
+// BatchError reports the tokens whose listings could not be fetched, by token id.
+type BatchError map[string]error
+
+func (e BatchError) Error() string {
+	ids := make([]string, 0, len(e))
+	for id := range e {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	msgs := make([]string, len(ids))
+	for i, id := range ids {
+		msgs[i] = id + ": " + e[id].Error()
+	}
+	return fmt.Sprintf("listings of %d tokens failed: %s", len(e), strings.Join(msgs, "; "))
+}
+
+// GetActiveListingsBatch fetches the Seaport listings of many tokens of one contract, grouping the token ids
+// into queries of up to ChunkSize ids that run Concurrency at a time, under the client Limiter.
+// The result has one entry per token id, in order; a failed query sets Err on each of its tokens.
+// A token id given more than once is queried once and its entries share the same listings.
+func (o Opensea) GetActiveListingsBatch(ctx context.Context, assetAddress string, tokenIDs []string, opts BatchOptions) []TokenListings {
+	size := opts.ChunkSize
+	if size <= 0 || size > MaxTokenIDsPerQuery {
+		size = MaxTokenIDsPerQuery
+	}
+	ret := make([]TokenListings, len(tokenIDs))
+	index := map[string][]int{}
+	var unique []string
+	for i, id := range tokenIDs {
+		ret[i].TokenID = id
+		if _, ok := index[id]; !ok {
+			unique = append(unique, id)
+		}
+		index[id] = append(index[id], i)
+	}
+	var chunks [][]string
+	for start := 0; start < len(unique); start += size {
+		end := start + size
+		if end > len(unique) {
+			end = len(unique)
+		}
+		chunks = append(chunks, unique[start:end])
+	}
+
+	var mu sync.Mutex
+	ran := make([]bool, len(chunks))
+	runBatch(ctx, len(chunks), opts.Concurrency, func(i int) {
+		orders, err := o.listingsOfTokens(ctx, assetAddress, chunks[i])
+		mu.Lock()
+		defer mu.Unlock()
+		ran[i] = true
+		if err != nil {
+			for _, id := range chunks[i] {
+				for _, j := range index[id] {
+					ret[j].Err = err
+				}
+			}
+			return
+		}
+		for _, ord := range orders {
+			_, id := listingAsset(ord)
+			for _, j := range index[id] {
+				ret[j].Orders = append(ret[j].Orders, ord)
+			}
+		}
+	})
+	for i, chunk := range chunks {
+		if ran[i] {
+			continue
+		}
+		for _, id := range chunk {
+			for _, j := range index[id] {
+				ret[j].Err = ctx.Err()
+			}
+		}
+	}
+	return ret
+}
+
+// listingsOfTokens follows the cursor of the v2 listings endpoint for one query of token ids.
+func (o Opensea) listingsOfTokens(ctx context.Context, assetAddress string, tokenIDs []string) ([]*OrderV2, error) {
+	q := url.Values{}
+	q.Set("limit", "50")
+	q.Set("asset_contract_address", assetAddress)
+	for _, id := range tokenIDs {
+		q.Add("token_ids", id)
+	}
+	var ret []*OrderV2
+	for {
+		b, err := o.GetPath(ctx, fmt.Sprintf("/v2/orders/%s/seaport/listings?%s", o.chain(), q.Encode()))
+		if err != nil {
+			return nil, err
+		}
+		var res OrdersV2Response
+		if err := json.Unmarshal(b, &res); err != nil {
+			return nil, err
+		}
+		o.labelPrices(res.Orders)
+		ret = append(ret, res.Orders...)
+		if res.Next == "" || len(res.Orders) == 0 {
+			return ret, nil
+		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		q.Set("cursor", res.Next)
+	}
+}
+
+// runBatch calls fn for 0..n-1 with at most concurrency calls at once, and stops starting new calls
+// once ctx is done.
+func runBatch(ctx context.Context, n, concurrency int, fn func(i int)) {
+	if concurrency <= 0 {
+		concurrency = defaultConcurrency
+	}
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return
+		}
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			fn(i)
+		}(i)
+	}
+	wg.Wait()
+}
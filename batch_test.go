@@ -0,0 +1,164 @@
+package opensea
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"golang.org/x/time/rate"
+)
+
+func TestGetActiveListingsBatch(t *testing.T) {
+	template := loadListing(t)
+	var requests, inFlight, maxInFlight int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		n := atomic.AddInt32(&inFlight, 1)
+		defer atomic.AddInt32(&inFlight, -1)
+		for {
+			m := atomic.LoadInt32(&maxInFlight)
+			if n <= m || atomic.CompareAndSwapInt32(&maxInFlight, m, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+
+		ids := r.URL.Query()["token_ids"]
+		assert.True(t, len(ids) <= 3)
+		for _, id := range ids {
+			if id == "13" {
+				w.WriteHeader(http.StatusInternalServerError)
+				return
+			}
+		}
+		// the first page holds the first token, the cursor the rest
+		if r.URL.Query().Get("cursor") == "" {
+			ids = ids[:1]
+		} else {
+			ids = ids[1:]
+		}
+		var res OrdersV2Response
+		for _, id := range ids {
+			ord := *template
+			params := *ord.ProtocolData.Parameters
+			params.Offer = []OfferItem{{ItemType: ItemERC721, Token: params.Offer[0].Token, IdentifierOrCriteria: id}}
+			ord.ProtocolData = &ProtocolData{Parameters: &params}
+			ord.OrderHash = "0x" + id
+			res.Orders = append(res.Orders, &ord)
+		}
+		if r.URL.Query().Get("cursor") == "" && len(r.URL.Query()["token_ids"]) > 1 {
+			res.Next = "page-2"
+		}
+		json.NewEncoder(w).Encode(res)
+	}))
+	defer srv.Close()
+	o := Opensea{API: srv.URL, httpClient: srv.Client()}
+
+	ids := []string{"1", "2", "3", "4", "5", "6", "7", "8", "9", "10", "11", "12", "13"}
+	res := o.GetActiveListingsBatch(context.Background(), "0xabc", ids, BatchOptions{ChunkSize: 3, Concurrency: 2})
+	assert.Equal(t, len(ids), len(res))
+	for i, v := range res {
+		assert.Equal(t, ids[i], v.TokenID)
+		if i >= 12 {
+			// 13 is alone in the last chunk
+			assert.NotNil(t, v.Err)
+			continue
+		}
+		assert.Nil(t, v.Err)
+		if assert.Equal(t, 1, len(v.Orders), v.TokenID) {
+			assert.Equal(t, "0x"+v.TokenID, v.Orders[0].OrderHash)
+		}
+	}
+	assert.Equal(t, int32(9), atomic.LoadInt32(&requests))
+	assert.True(t, atomic.LoadInt32(&maxInFlight) <= 2)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	res = o.GetActiveListingsBatch(ctx, "0xabc", ids[:2], BatchOptions{})
+	assert.NotNil(t, res[0].Err)
+	assert.NotNil(t, res[1].Err)
+}
+
+func TestGetActiveListingsBatchDuplicateIDs(t *testing.T) {
+	template := loadListing(t)
+	var queried []string
+	var mu sync.Mutex
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ids := r.URL.Query()["token_ids"]
+		mu.Lock()
+		queried = append(queried, ids...)
+		mu.Unlock()
+		var res OrdersV2Response
+		for _, id := range ids {
+			ord := *template
+			params := *ord.ProtocolData.Parameters
+			params.Offer = []OfferItem{{ItemType: ItemERC721, Token: params.Offer[0].Token, IdentifierOrCriteria: id}}
+			ord.ProtocolData = &ProtocolData{Parameters: &params}
+			ord.OrderHash = "0x" + id
+			res.Orders = append(res.Orders, &ord)
+		}
+		json.NewEncoder(w).Encode(res)
+	}))
+	defer srv.Close()
+	o := Opensea{API: srv.URL, httpClient: srv.Client()}
+
+	ids := []string{"1", "2", "1", "3", "1"}
+	res := o.GetActiveListingsBatch(context.Background(), "0xabc", ids, BatchOptions{ChunkSize: 2})
+	assert.Equal(t, len(ids), len(res))
+	for i, v := range res {
+		assert.Equal(t, ids[i], v.TokenID)
+		assert.Nil(t, v.Err)
+		if assert.Equal(t, 1, len(v.Orders), v.TokenID) {
+			assert.Equal(t, "0x"+v.TokenID, v.Orders[0].OrderHash)
+		}
+	}
+	sort.Strings(queried)
+	assert.Equal(t, []string{"1", "2", "3"}, queried)
+}
+
+func TestGetActiveListingsPerTokenErrors(t *testing.T) {
+	var mu sync.Mutex
+	var seen []time.Time
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		seen = append(seen, time.Now())
+		mu.Unlock()
+		parts := strings.Split(r.URL.Path, "/")
+		id := parts[len(parts)-2]
+		if id == "bad" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		fmt.Fprintf(w, `{"listings": [{"order_hash": "0x%s"}]}`, id)
+	}))
+	defer srv.Close()
+	o := Opensea{API: srv.URL, httpClient: srv.Client(), Limiter: rate.NewLimiter(rate.Inf, 1)}
+
+	// one failed token fails the whole call
+	list, err := o.GetActiveListingsWithContext(context.Background(), "0xabc", []string{"1", "bad", "2"}, 10*time.Millisecond)
+	assert.Nil(t, list)
+	batchErr, ok := err.(BatchError)
+	if assert.True(t, ok) {
+		assert.Equal(t, 1, len(batchErr))
+		assert.NotNil(t, batchErr["bad"])
+	}
+	assert.Equal(t, 3, len(seen))
+	// the interval still spaces the requests
+	assert.True(t, seen[2].Sub(seen[0]) >= 15*time.Millisecond)
+
+	list, err = o.GetActiveListingsWithContext(context.Background(), "0xabc", []string{"1", "2"}, 0)
+	assert.Nil(t, err)
+	if assert.Equal(t, 2, len(list)) {
+		assert.Equal(t, "0x1", list[0].OrderHash)
+		assert.Equal(t, "0x2", list[1].OrderHash)
+	}
+}
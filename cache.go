@@ -0,0 +1,223 @@
+package opensea
+
+import (
+	"container/list"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math/big"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// CacheEntry is a cached response body. Entries are kept past Expires so that they can be revalidated
+// with their ETag.
+type CacheEntry struct {
+	Body    []byte    `json:"body"`
+	ETag    string    `json:"etag"`
+	Expires time.Time `json:"expires"`
+}
+
+// Cache stores responses of GET requests, keyed by their URL.
+type Cache interface {
+	Get(key string) (CacheEntry, bool)
+	Set(key string, entry CacheEntry)
+	Delete(key string)
+}
+
+// CacheRule caches the GET paths matching Pattern, in the syntax of path.Match, for TTL.
+// Query strings are not matched. A TTL of 0 disables caching of the paths.
+type CacheRule struct {
+	Pattern string
+	TTL     time.Duration
+}
+
+// DefaultCacheRules cache the mostly static metadata of assets, contracts and collections.
+var DefaultCacheRules = []CacheRule{
+	{Pattern: "/api/v1/asset/*/*", TTL: time.Hour},
+	{Pattern: "/api/v1/asset_contract/*", TTL: 24 * time.Hour},
+	{Pattern: "/api/v1/collection/*", TTL: time.Hour},
+}
+
+func (o Opensea) cacheTTL(p string) (time.Duration, bool) {
+	if o.Cache == nil {
+		return 0, false
+	}
+	rules := o.CacheRules
+	if rules == nil {
+		rules = DefaultCacheRules
+	}
+	if i := strings.IndexByte(p, '?'); i >= 0 {
+		p = p[:i]
+	}
+	for _, r := range rules {
+		if ok, _ := path.Match(r.Pattern, p); ok {
+			return r.TTL, r.TTL > 0
+		}
+	}
+	return 0, false
+}
+
+func (o Opensea) getCached(ctx context.Context, url string, ttl time.Duration) ([]byte, error) {
+	entry, ok := o.Cache.Get(url)
+	if ok && time.Now().Before(entry.Expires) {
+		return entry.Body, nil
+	}
+	etag := ""
+	if ok {
+		etag = entry.ETag
+	}
+	body, newETag, notModified, err := o.fetch(ctx, url, etag)
+	if err != nil {
+		return nil, err
+	}
+	if notModified {
+		body = entry.Body
+		if newETag == "" {
+			newETag = entry.ETag
+		}
+	}
+	o.Cache.Set(url, CacheEntry{Body: body, ETag: newETag, Expires: time.Now().Add(ttl)})
+	return body, nil
+}
+
+// Invalidate drops the cached response of path.
+func (o Opensea) Invalidate(path string) {
+	if o.Cache != nil {
+		o.Cache.Delete(o.API + path)
+	}
+}
+
+func (o Opensea) InvalidateAsset(assetContractAddress string, tokenID *big.Int) {
+	o.Invalidate(fmt.Sprintf("/api/v1/asset/%s/%s", assetContractAddress, tokenID.String()))
+}
+
+func (o Opensea) InvalidateContract(assetContractAddress string) {
+	o.Invalidate("/api/v1/asset_contract/" + assetContractAddress)
+}
+
+func (o Opensea) InvalidateCollection(slug string) {
+	o.Invalidate(fmt.Sprintf("/api/v1/collection/%s", slug))
+}
+
+// MemoryCache is an in-memory Cache that evicts the least recently used entries beyond its size.
+type MemoryCache struct {
+	mu      sync.Mutex
+	size    int
+	order   *list.List
+	entries map[string]*list.Element
+}
+
+type memoryItem struct {
+	key   string
+	entry CacheEntry
+}
+
+func NewMemoryCache(size int) *MemoryCache {
+	return &MemoryCache{size: size, order: list.New(), entries: map[string]*list.Element{}}
+}
+
+func (c *MemoryCache) Get(key string) (CacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	el, ok := c.entries[key]
+	if !ok {
+		return CacheEntry{}, false
+	}
+	c.order.MoveToFront(el)
+	return el.Value.(*memoryItem).entry, true
+}
+
+func (c *MemoryCache) Set(key string, entry CacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		el.Value.(*memoryItem).entry = entry
+		c.order.MoveToFront(el)
+		return
+	}
+	c.entries[key] = c.order.PushFront(&memoryItem{key: key, entry: entry})
+	for c.size > 0 && c.order.Len() > c.size {
+		el := c.order.Back()
+		c.order.Remove(el)
+		delete(c.entries, el.Value.(*memoryItem).key)
+	}
+}
+
+func (c *MemoryCache) Delete(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if el, ok := c.entries[key]; ok {
+		c.order.Remove(el)
+		delete(c.entries, key)
+	}
+}
+
+func (c *MemoryCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len()
+}
+
+// DiskCache is a Cache keeping one JSON file per entry in a directory, so that it survives restarts.
+type DiskCache struct {
+	dir string
+}
+
+func NewDiskCache(dir string) (*DiskCache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &DiskCache{dir: dir}, nil
+}
+
+func (c *DiskCache) file(key string) string {
+	sum := sha256.Sum256([]byte(key))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".json")
+}
+
+func (c *DiskCache) Get(key string) (CacheEntry, bool) {
+	b, err := ioutil.ReadFile(c.file(key))
+	if err != nil {
+		return CacheEntry{}, false
+	}
+	var entry CacheEntry
+	if err := json.Unmarshal(b, &entry); err != nil {
+		return CacheEntry{}, false
+	}
+	return entry, true
+}
+
+// Set writes the entry to a temporary file first, so that readers never see half an entry.
+func (c *DiskCache) Set(key string, entry CacheEntry) {
+	b, err := json.Marshal(entry)
+	if err != nil {
+		return
+	}
+	tmp, err := ioutil.TempFile(c.dir, "tmp-")
+	if err != nil {
+		return
+	}
+	_, err = tmp.Write(b)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+		return
+	}
+	if err := os.Rename(tmp.Name(), c.file(key)); err != nil {
+		os.Remove(tmp.Name())
+	}
+}
+
+func (c *DiskCache) Delete(key string) {
+	os.Remove(c.file(key))
+}
@@ -0,0 +1,112 @@
+package opensea
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testServer answers every path with a body naming the request count, and 304 to a matching If-None-Match.
+func testServer(t *testing.T, calls *int32) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		n := atomic.AddInt32(calls, 1)
+		if r.Header.Get("If-None-Match") == `"v1"` {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", `"v1"`)
+		fmt.Fprintf(w, `{"slug": "call-%d"}`, n)
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func testClient(srv *httptest.Server) Opensea {
+	return Opensea{API: srv.URL, httpClient: srv.Client()}
+}
+
+func TestCacheRules(t *testing.T) {
+	var calls int32
+	o := testClient(testServer(t, &calls))
+	o.Cache = NewMemoryCache(10)
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		b, err := o.GetPath(ctx, "/api/v1/collection/doodles")
+		assert.Nil(t, err)
+		assert.Equal(t, `{"slug": "call-1"}`, string(b))
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	// stats and lists are not cached by the default rules
+	_, _ = o.GetPath(ctx, "/api/v1/collection/doodles/stats")
+	_, _ = o.GetPath(ctx, "/api/v1/collection/doodles/stats")
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+
+	o.InvalidateCollection("doodles")
+	b, _ := o.GetPath(ctx, "/api/v1/collection/doodles")
+	assert.Equal(t, `{"slug": "call-4"}`, string(b))
+
+	o.CacheRules = []CacheRule{{Pattern: "/api/v1/asset/*/*", TTL: time.Hour}}
+	_, _ = o.GetPath(ctx, "/api/v1/collection/doodles")
+	assert.Equal(t, int32(5), atomic.LoadInt32(&calls))
+	_, _ = o.GetPath(ctx, "/api/v1/asset/0xabc/1")
+	o.InvalidateAsset("0xabc", big.NewInt(1))
+	_, _ = o.GetPath(ctx, "/api/v1/asset/0xabc/1")
+	assert.Equal(t, int32(7), atomic.LoadInt32(&calls))
+}
+
+func TestCacheRevalidatesWithETag(t *testing.T) {
+	var calls int32
+	o := testClient(testServer(t, &calls))
+	o.Cache = NewMemoryCache(10)
+	o.CacheRules = []CacheRule{{Pattern: "/api/v1/asset_contract/*", TTL: time.Nanosecond}}
+	ctx := context.Background()
+
+	b, err := o.GetPath(ctx, "/api/v1/asset_contract/0xabc")
+	assert.Nil(t, err)
+	time.Sleep(time.Millisecond)
+	again, err := o.GetPath(ctx, "/api/v1/asset_contract/0xabc")
+	assert.Nil(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+	assert.Equal(t, b, again)
+}
+
+func TestMemoryCacheEvictsLeastRecentlyUsed(t *testing.T) {
+	c := NewMemoryCache(2)
+	c.Set("a", CacheEntry{Body: []byte("a")})
+	c.Set("b", CacheEntry{Body: []byte("b")})
+	c.Get("a")
+	c.Set("c", CacheEntry{Body: []byte("c")})
+	_, ok := c.Get("b")
+	assert.False(t, ok)
+	_, ok = c.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, 2, c.Len())
+}
+
+func TestDiskCache(t *testing.T) {
+	dir := t.TempDir()
+	c, err := NewDiskCache(dir)
+	assert.Nil(t, err)
+	expires := time.Now().Add(time.Hour).Round(0)
+	c.Set("https://api.opensea.io/api/v1/collection/doodles", CacheEntry{Body: []byte(`{}`), ETag: `"v1"`, Expires: expires})
+
+	reopened, _ := NewDiskCache(dir)
+	e, ok := reopened.Get("https://api.opensea.io/api/v1/collection/doodles")
+	assert.True(t, ok)
+	assert.Equal(t, `{}`, string(e.Body))
+	assert.Equal(t, `"v1"`, e.ETag)
+	assert.True(t, expires.Equal(e.Expires))
+
+	reopened.Delete("https://api.opensea.io/api/v1/collection/doodles")
+	_, ok = c.Get("https://api.opensea.io/api/v1/collection/doodles")
+	assert.False(t, ok)
+}
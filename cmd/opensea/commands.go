@@ -0,0 +1,364 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"math"
+	"math/big"
+	"strconv"
+
+	"github.com/jumpblock/go-opensea"
+)
+
+// seaport11 is the Seaport 1.1 contract, the protocol of most listings.
+const seaport11 = "0x00000000006c3852cbef3e08e8df289169ede581"
+
+var commands = []command{
+	{
+		name:    "asset",
+		args:    "<contract> <token_id>",
+		summary: "show an asset",
+		run:     runAsset,
+	},
+	{
+		name:    "contract",
+		args:    "<contract>",
+		summary: "show an asset contract",
+		run:     runContract,
+	},
+	{
+		name:    "collection",
+		args:    "<slug>",
+		summary: "show a collection",
+		run:     runCollection,
+	},
+	{
+		name:    "stats",
+		args:    "<slug>",
+		summary: "show the statistics of a collection",
+		run:     runStats,
+	},
+	{
+		name:    "events",
+		summary: "list events, newest first",
+		paged:   true,
+		flags:   eventFlags,
+		run:     runEvents,
+	},
+	{
+		name:    "listings",
+		args:    "[<contract> [<token_id>...]]",
+		summary: "list Seaport listings",
+		paged:   true,
+		flags:   orderFlags,
+		run:     runOrders("listings"),
+	},
+	{
+		name:    "offers",
+		args:    "[<contract> [<token_id>...]]",
+		summary: "list Seaport offers",
+		paged:   true,
+		flags:   orderFlags,
+		run:     runOrders("offers"),
+	},
+	{
+		name:    "fulfill",
+		args:    "<order_hash>",
+		summary: "get the data to fulfill a listing",
+		flags:   fulfillFlags,
+		run:     runFulfill,
+	},
+}
+
+func runAsset(ctx context.Context, o *opensea.Opensea, opts *options, args []string) (*result, error) {
+	if len(args) != 2 {
+		return nil, errUsage
+	}
+	id, ok := new(big.Int).SetString(args[1], 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid token id %q", args[1])
+	}
+	a, err := o.GetSingleAssetWithContext(ctx, args[0], id)
+	if err != nil {
+		return nil, err
+	}
+	var contract, collection, owner string
+	if a.AssetContract != nil {
+		contract = a.AssetContract.Address.String()
+	}
+	if a.Collection != nil {
+		collection = a.Collection.Slug
+	}
+	if a.Owner != nil {
+		owner = a.Owner.Address.String()
+	}
+	return &result{
+		value:   a,
+		single:  true,
+		columns: []string{"contract", "token_id", "name", "collection", "owner", "num_sales", "permalink"},
+		rows:    [][]string{{contract, a.TokenID, a.Name, collection, owner, strconv.FormatInt(a.NumSales, 10), a.Permalink}},
+	}, nil
+}
+
+func runContract(ctx context.Context, o *opensea.Opensea, opts *options, args []string) (*result, error) {
+	if len(args) != 1 {
+		return nil, errUsage
+	}
+	c, err := o.GetSingleContractWithContext(ctx, args[0])
+	if err != nil {
+		return nil, err
+	}
+	return &result{
+		value:   c,
+		single:  true,
+		columns: []string{"address", "name", "symbol", "schema", "collection", "total_supply", "seller_fee_basis_points", "payout_address"},
+		rows: [][]string{{
+			c.Address.String(), c.Name, c.Symbol, c.SchemaName, c.Collection.Slug,
+			string(c.TotalSupply), strconv.FormatInt(c.SellerFeeBasisPoints, 10), c.PayoutAddress.String(),
+		}},
+	}, nil
+}
+
+func runCollection(ctx context.Context, o *opensea.Opensea, opts *options, args []string) (*result, error) {
+	if len(args) != 1 {
+		return nil, errUsage
+	}
+	c, err := o.GetSingleCollectionWithContext(ctx, args[0])
+	if err != nil {
+		return nil, err
+	}
+	return &result{
+		value:   c,
+		single:  true,
+		columns: []string{"slug", "name", "floor_price", "total_volume", "num_owners", "total_supply", "created_date", "external_url"},
+		rows: [][]string{{
+			c.Slug, c.Name, c.Stats.FloorPrice.Units(), c.Stats.TotalVolume.Units(),
+			formatFloat(c.Stats.NumOwners), formatFloat(c.Stats.TotalSupply), c.CreatedDate, c.ExternalUrl,
+		}},
+	}, nil
+}
+
+func runStats(ctx context.Context, o *opensea.Opensea, opts *options, args []string) (*result, error) {
+	if len(args) != 1 {
+		return nil, errUsage
+	}
+	s, err := o.GetCollectionStatsWithContext(ctx, args[0])
+	if err != nil {
+		return nil, err
+	}
+	return &result{
+		value:  s,
+		single: true,
+		columns: []string{
+			"floor_price", "one_day_volume", "one_day_sales", "seven_day_volume", "seven_day_sales",
+			"thirty_day_volume", "total_volume", "total_sales", "num_owners", "total_supply", "market_cap",
+		},
+		rows: [][]string{{
+			s.FloorPrice.Units(), s.OneDayVolume.Units(), formatFloat(s.OneDaySales), s.SevenDayVolume.Units(), formatFloat(s.SevenDaySales),
+			s.ThirtyDayVolume.Units(), s.TotalVolume.Units(), formatFloat(s.TotalSales), formatFloat(s.NumOwners), formatFloat(s.TotalSupply), s.MarketCap.Units(),
+		}},
+	}, nil
+}
+
+type eventOptions struct {
+	collection, contract, token, account, eventType, after, before string
+}
+
+func eventFlags(fs *flag.FlagSet) interface{} {
+	eventParams := &eventOptions{}
+	fs.StringVar(&eventParams.collection, "collection", "", "collection slug")
+	fs.StringVar(&eventParams.contract, "contract", "", "asset contract address")
+	fs.StringVar(&eventParams.token, "token", "", "token id, with --contract")
+	fs.StringVar(&eventParams.account, "account", "", "account address")
+	fs.StringVar(&eventParams.eventType, "type", "", "event type, such as successful, created or transfer")
+	fs.StringVar(&eventParams.after, "after", "", "only events after this time, RFC 3339 or unix seconds")
+	fs.StringVar(&eventParams.before, "before", "", "only events before this time, RFC 3339 or unix seconds")
+	return eventParams
+}
+
+func runEvents(ctx context.Context, o *opensea.Opensea, opts *options, args []string) (*result, error) {
+	if len(args) != 0 {
+		return nil, errUsage
+	}
+	eventParams := opts.flags.(*eventOptions)
+	p := opensea.NewRetrievingEventsParams()
+	p.CollectionSlug = eventParams.collection
+	p.AssetContractAddress = eventParams.contract
+	if eventParams.token != "" {
+		// the events endpoint filters on 32 bit token ids only
+		id, err := strconv.ParseInt(eventParams.token, 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid token id %q for events, which take at most %d", eventParams.token, math.MaxInt32)
+		}
+		p.TokenID = int32(id)
+	}
+	p.AccountAddress = eventParams.account
+	p.EventType = opensea.EventType(eventParams.eventType)
+	p.Cursor = opts.cursor
+	p.Limit = opts.limit
+	for _, v := range []struct {
+		flag string
+		dst  *int64
+	}{{eventParams.after, &p.OccurredAfter}, {eventParams.before, &p.OccurredBefore}} {
+		if v.flag == "" {
+			continue
+		}
+		t, err := opensea.ParseTime(v.flag)
+		if err != nil {
+			return nil, err
+		}
+		*v.dst = t.Unix()
+	}
+
+	page, err := o.RetrievingEventsPage(ctx, p)
+	if err != nil {
+		return nil, err
+	}
+	res := &result{
+		value:   page,
+		next:    page.Next,
+		columns: []string{"id", "time", "type", "collection", "token_id", "price", "from", "to", "transaction"},
+	}
+	for _, e := range page.AssetEvents {
+		var tokenID, from, to, tx string
+		if e.Asset != nil {
+			tokenID = e.Asset.TokenID
+		}
+		switch {
+		case e.Seller != nil:
+			from = e.Seller.Address.String()
+		case e.FromAccount != nil:
+			from = e.FromAccount.Address.String()
+		}
+		switch {
+		case e.WinnerAccount != nil:
+			to = e.WinnerAccount.Address.String()
+		case e.ToAccount != nil:
+			to = e.ToAccount.Address.String()
+		}
+		if e.Transaction != nil {
+			tx = e.Transaction.TransactionHash
+		}
+		res.rows = append(res.rows, []string{
+			strconv.FormatUint(e.ID, 10), opensea.TimeNano(e.OccurredAt()).String(), string(e.EventType),
+			e.CollectionSlug, tokenID, eventPrice(o, e), from, to, tx,
+		})
+	}
+	return res, nil
+}
+
+func eventPrice(o *opensea.Opensea, e *opensea.Event) string {
+	if e.TotalPrice.Int == nil && e.BidAmount.Int == nil {
+		return ""
+	}
+	amount, token, err := opensea.DefaultTokenRegistry.EventPrice(o.Chain, e)
+	if err != nil {
+		return amount.String()
+	}
+	return amount.Units() + " " + token.Symbol
+}
+
+type orderOptions struct {
+	maker, taker, orderBy, direction string
+}
+
+func orderFlags(fs *flag.FlagSet) interface{} {
+	orderParams := &orderOptions{}
+	fs.StringVar(&orderParams.maker, "maker", "", "only orders made by this address")
+	fs.StringVar(&orderParams.taker, "taker", "", "only orders reserved for this address")
+	fs.StringVar(&orderParams.orderBy, "order-by", "", "created_date or eth_price")
+	fs.StringVar(&orderParams.direction, "direction", "", "asc or desc")
+	return orderParams
+}
+
+func runOrders(side string) func(ctx context.Context, o *opensea.Opensea, opts *options, args []string) (*result, error) {
+	return func(ctx context.Context, o *opensea.Opensea, opts *options, args []string) (*result, error) {
+		orderParams := opts.flags.(*orderOptions)
+		p := opensea.OrdersV2Params{
+			Maker:          orderParams.maker,
+			Taker:          orderParams.taker,
+			OrderBy:        orderParams.orderBy,
+			OrderDirection: orderParams.direction,
+			Cursor:         opts.cursor,
+			Limit:          opts.limit,
+		}
+		if len(args) > 0 {
+			p.AssetContractAddress, p.TokenIDs = args[0], args[1:]
+		}
+		if len(p.TokenIDs) > opensea.MaxTokenIDsPerQuery {
+			return nil, fmt.Errorf("at most %d token ids per query", opensea.MaxTokenIDsPerQuery)
+		}
+		get := o.GetListingsV2Page
+		if side == "offers" {
+			get = o.GetOffersV2Page
+		}
+		page, err := get(ctx, p)
+		if err != nil {
+			return nil, err
+		}
+		res := &result{
+			value:   page,
+			next:    page.Next,
+			columns: []string{"order_hash", "maker", "price", "listed", "expires"},
+		}
+		for _, v := range page.Orders {
+			var maker string
+			if v.Maker != nil {
+				maker = v.Maker.Address.String()
+			}
+			price := v.CurrentPrice.String()
+			if amount, token, err := opensea.DefaultTokenRegistry.OrderPrice(o.Chain, v); err == nil {
+				price = amount.Units() + " " + token.Symbol
+			}
+			res.rows = append(res.rows, []string{v.OrderHash, maker, price, v.ListingTime.String(), v.ExpirationTime.String()})
+		}
+		return res, nil
+	}
+}
+
+type fulfillOptions struct {
+	fulfiller, protocol string
+}
+
+func fulfillFlags(fs *flag.FlagSet) interface{} {
+	fulfillParams := &fulfillOptions{}
+	fs.StringVar(&fulfillParams.fulfiller, "fulfiller", "", "address of the buyer (required)")
+	fs.StringVar(&fulfillParams.protocol, "protocol-address", seaport11, "Seaport contract of the listing")
+	return fulfillParams
+}
+
+func runFulfill(ctx context.Context, o *opensea.Opensea, opts *options, args []string) (*result, error) {
+	if len(args) != 1 {
+		return nil, errUsage
+	}
+	fulfillParams := opts.flags.(*fulfillOptions)
+	if fulfillParams.fulfiller == "" {
+		return nil, errors.New("--fulfiller is required")
+	}
+	f, err := o.GetListingFulfillmentWithContext(ctx,
+		opensea.ListingParam{Hash: args[0], Chain: string(o.Chain), ProtocolAddress: fulfillParams.protocol},
+		opensea.FulfillerParam{Address: fulfillParams.fulfiller},
+	)
+	if err != nil {
+		return nil, err
+	}
+	res := &result{
+		value:   f,
+		single:  true,
+		columns: []string{"protocol", "offerer", "zone", "start_time", "end_time", "signature"},
+	}
+	for _, v := range f.FulfillmentData.Orders {
+		row := []string{f.Protocol, "", "", "", "", v.Signature}
+		if p := v.Parameters; p != nil {
+			row[1], row[2], row[3], row[4] = p.Offerer.String(), p.Zone.String(), p.StartTimeAt().String(), p.EndTimeAt().String()
+		}
+		res.rows = append(res.rows, row)
+	}
+	return res, nil
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
@@ -0,0 +1,260 @@
+// Command opensea queries the OpenSea API from the shell.
+//
+//	opensea [command] [flags] [arguments]
+//
+// The API key is read from OPENSEA_API_KEY, or from the api_key of the JSON config file at
+// OPENSEA_CONFIG, by default opensea/config.json in the user config directory. Run
+// "opensea help" for the commands.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/jumpblock/go-opensea"
+)
+
+// config is the content of the config file; the environment overrides it.
+type config struct {
+	APIKey string `json:"api_key"`
+	Chain  string `json:"chain"`
+	APIURL string `json:"api_url"`
+}
+
+var apiURLs = map[opensea.Chain]string{
+	opensea.ChainEthereum: "https://api.opensea.io",
+	opensea.ChainPolygon:  "https://api.opensea.io",
+	opensea.ChainRinkeby:  "https://rinkeby-api.opensea.io",
+	opensea.ChainGoerli:   "https://testnets-api.opensea.io",
+}
+
+type command struct {
+	name    string
+	args    string
+	summary string
+	// paged commands take --limit and --cursor
+	paged bool
+	// flags registers the flags of the command beyond the common ones, bound to a new value that
+	// run finds in options.flags
+	flags func(fs *flag.FlagSet) interface{}
+	run   func(ctx context.Context, o *opensea.Opensea, opts *options, args []string) (*result, error)
+}
+
+// options are the common flags, and the flags of the command.
+type options struct {
+	chain  string
+	json   bool
+	table  bool
+	csv    bool
+	limit  int
+	cursor string
+	flags  interface{}
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+	os.Exit(run(ctx, os.Args[1:], os.Stdout, os.Stderr))
+}
+
+// run executes the command line args and returns the exit code.
+func run(ctx context.Context, args []string, stdout, stderr io.Writer) int {
+	if len(args) == 0 || args[0] == "help" || args[0] == "-h" || args[0] == "--help" {
+		usage(stdout)
+		return 0
+	}
+	cmd := findCommand(args[0])
+	if cmd == nil {
+		fmt.Fprintf(stderr, "opensea: unknown command %q\n", args[0])
+		usage(stderr)
+		return 2
+	}
+
+	opts := &options{}
+	fs := flag.NewFlagSet(cmd.name, flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	fs.StringVar(&opts.chain, "chain", "", "chain: ethereum, matic, goerli or rinkeby (default from config, else ethereum)")
+	fs.BoolVar(&opts.json, "json", false, "write JSON (the default for single objects)")
+	fs.BoolVar(&opts.table, "table", false, "write an aligned table (the default for lists)")
+	fs.BoolVar(&opts.csv, "csv", false, "write CSV")
+	if cmd.paged {
+		fs.IntVar(&opts.limit, "limit", 50, "results per page")
+		fs.StringVar(&opts.cursor, "cursor", "", "cursor of the page to fetch, as printed after the previous page")
+	}
+	if cmd.flags != nil {
+		opts.flags = cmd.flags(fs)
+	}
+	fs.Usage = func() {
+		fmt.Fprintf(stderr, "usage: opensea %s [flags] %s\n\n%s\n\n", cmd.name, cmd.args, cmd.summary)
+		fs.PrintDefaults()
+	}
+	rest, err := parseInterspersed(fs, args[1:])
+	if errors.Is(err, flag.ErrHelp) {
+		return 0
+	}
+	if err != nil {
+		return 2
+	}
+	format, err := opts.format()
+	if err != nil {
+		fmt.Fprintln(stderr, "opensea:", err)
+		return 2
+	}
+
+	o, err := newClient(opts.chain)
+	if err != nil {
+		fmt.Fprintln(stderr, "opensea:", err)
+		return 1
+	}
+	res, err := cmd.run(ctx, o, opts, rest)
+	if errors.Is(err, errUsage) {
+		fs.Usage()
+		return 2
+	}
+	if err != nil {
+		fmt.Fprintln(stderr, "opensea:", err)
+		return 1
+	}
+	if err := res.write(stdout, format); err != nil {
+		fmt.Fprintln(stderr, "opensea:", err)
+		return 1
+	}
+	if res.next != "" && format != formatJSON {
+		fmt.Fprintf(stderr, "next page: --cursor %s\n", res.next)
+	}
+	return 0
+}
+
+var errUsage = errors.New("usage")
+
+// parseInterspersed parses flags placed before, between or after the arguments.
+func parseInterspersed(fs *flag.FlagSet, args []string) ([]string, error) {
+	var rest []string
+	for {
+		if err := fs.Parse(args); err != nil {
+			return nil, err
+		}
+		args = fs.Args()
+		if len(args) == 0 {
+			return rest, nil
+		}
+		if args[0] == "--" {
+			return append(rest, args[1:]...), nil
+		}
+		rest = append(rest, args[0])
+		args = args[1:]
+	}
+}
+
+func (opts *options) format() (format, error) {
+	n := 0
+	f := formatDefault
+	if opts.json {
+		n, f = n+1, formatJSON
+	}
+	if opts.table {
+		n, f = n+1, formatTable
+	}
+	if opts.csv {
+		n, f = n+1, formatCSV
+	}
+	if n > 1 {
+		return f, errors.New("choose one of --json, --table and --csv")
+	}
+	return f, nil
+}
+
+func newClient(chainFlag string) (*opensea.Opensea, error) {
+	cfg, err := loadConfig()
+	if err != nil {
+		return nil, err
+	}
+	if key := os.Getenv("OPENSEA_API_KEY"); key != "" {
+		cfg.APIKey = key
+	}
+	if u := os.Getenv("OPENSEA_API_URL"); u != "" {
+		cfg.APIURL = u
+	}
+	chain := opensea.Chain(cfg.Chain)
+	if chainFlag != "" {
+		chain = opensea.Chain(chainFlag)
+	}
+	if chain == "" {
+		chain = opensea.ChainEthereum
+	}
+	if chain == "polygon" {
+		chain = opensea.ChainPolygon
+	}
+	if _, ok := apiURLs[chain]; !ok {
+		return nil, fmt.Errorf("unknown chain %q", chain)
+	}
+
+	o, err := opensea.NewOpensea(cfg.APIKey)
+	if err != nil {
+		return nil, err
+	}
+	o.Chain = chain
+	o.API = apiURLs[chain]
+	if cfg.APIURL != "" {
+		o.API = strings.TrimRight(cfg.APIURL, "/")
+	}
+	return o, nil
+}
+
+func loadConfig() (config, error) {
+	var cfg config
+	path := os.Getenv("OPENSEA_CONFIG")
+	if path == "" {
+		dir, err := os.UserConfigDir()
+		if err != nil {
+			return cfg, nil
+		}
+		path = filepath.Join(dir, "opensea", "config.json")
+	}
+	b, err := ioutil.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, err
+	}
+	if err := json.Unmarshal(b, &cfg); err != nil {
+		return cfg, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return cfg, nil
+}
+
+func findCommand(name string) *command {
+	for i := range commands {
+		if commands[i].name == name {
+			return &commands[i]
+		}
+	}
+	return nil
+}
+
+func usage(w io.Writer) {
+	fmt.Fprintln(w, "usage: opensea <command> [flags] [arguments]")
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, "commands:")
+	names := make([]string, len(commands))
+	for i, c := range commands {
+		names[i] = fmt.Sprintf("  %-11s %s", c.name, c.summary)
+	}
+	sort.Strings(names)
+	for _, n := range names {
+		fmt.Fprintln(w, n)
+	}
+	fmt.Fprintln(w)
+	fmt.Fprintln(w, `Run "opensea <command> -h" for the flags of a command.`)
+}
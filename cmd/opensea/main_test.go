@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeAPI serves the fixtures of test-files and records the queries it received.
+func fakeAPI(t *testing.T) *[]string {
+	var queries []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "test-key", r.Header.Get("X-API-KEY"))
+		queries = append(queries, r.URL.Path+"?"+r.URL.RawQuery)
+		switch r.URL.Path {
+		case "/api/v1/collection/doodles-official/stats":
+			serveFile(t, w, "opensea-stats-doodles.json")
+		case "/api/v1/events":
+			serveFile(t, w, "opensea-events.json")
+		case "/v2/orders/matic/seaport/listings":
+			order, err := ioutil.ReadFile("../../test-files/listings-v2.json")
+			assert.Nil(t, err)
+			json.NewEncoder(w).Encode(map[string]interface{}{"next": "page2", "orders": []json.RawMessage{order}})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	t.Setenv("OPENSEA_API_URL", srv.URL)
+	t.Setenv("OPENSEA_API_KEY", "test-key")
+	t.Setenv("OPENSEA_CONFIG", filepath.Join(t.TempDir(), "missing.json"))
+	return &queries
+}
+
+func serveFile(t *testing.T, w http.ResponseWriter, name string) {
+	b, err := ioutil.ReadFile("../../test-files/" + name)
+	assert.Nil(t, err)
+	w.Write(b)
+}
+
+func runCLI(args ...string) (int, string, string) {
+	var stdout, stderr bytes.Buffer
+	code := run(context.Background(), args, &stdout, &stderr)
+	return code, stdout.String(), stderr.String()
+}
+
+func TestStats(t *testing.T) {
+	fakeAPI(t)
+
+	code, out, _ := runCLI("stats", "doodles-official", "--table")
+	assert.Equal(t, 0, code)
+	assert.Contains(t, out, "FLOOR_PRICE        2\n")
+	assert.Contains(t, out, "TOTAL_SALES        11433\n")
+
+	code, out, _ = runCLI("stats", "doodles-official")
+	assert.Equal(t, 0, code)
+	var stats map[string]interface{}
+	assert.Nil(t, json.Unmarshal([]byte(out), &stats))
+	assert.Equal(t, 2.0, stats["floor_price"])
+}
+
+func TestEvents(t *testing.T) {
+	queries := fakeAPI(t)
+
+	code, out, _ := runCLI("events", "--collection", "forgotten-runes-mafriends", "--type", "successful", "--limit", "20", "--after", "2021-08-01T00:00:00Z", "--csv")
+	assert.Equal(t, 0, code)
+	assert.Contains(t, (*queries)[0], "collection_slug=forgotten-runes-mafriends")
+	assert.Contains(t, (*queries)[0], "event_type=successful")
+	assert.Contains(t, (*queries)[0], "limit=20")
+	assert.Contains(t, (*queries)[0], "occurred_after=1627776000")
+
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	assert.Equal(t, "id,time,type,collection,token_id,price,from,to,transaction", lines[0])
+	assert.Contains(t, out, "651665248,2021-08-26T22:48:39.93615Z,successful,forgotten-runes-mafriends,")
+	assert.Contains(t, out, ",0.2 WETH,0xbc0c08bc6c8e949d54db31eddc8ee809ef323ec3,0x45aef14112f92bdc3fa08ca0d85c8a96af47c490,")
+
+	code, _, _ = runCLI("events", "--contract", "0x495f947276749ce646f68ac8c248420045cb7b5e", "--token", "2147483647")
+	assert.Equal(t, 0, code)
+	assert.Contains(t, (*queries)[1], "token_id=2147483647")
+	// the flags of the previous run are gone
+	assert.NotContains(t, (*queries)[1], "collection_slug")
+	assert.NotContains(t, (*queries)[1], "event_type")
+
+	// larger token ids do not wrap around
+	code, _, errOut := runCLI("events", "--contract", "0x495f947276749ce646f68ac8c248420045cb7b5e", "--token", "2147483648")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, errOut, `invalid token id "2147483648" for events, which take at most 2147483647`)
+	assert.Len(t, *queries, 2)
+}
+
+func TestListingsPaginate(t *testing.T) {
+	queries := fakeAPI(t)
+
+	code, out, errOut := runCLI("listings", "--chain", "matic", "0x8a90cab2b38dba80c64b7734e58ee1db38b8992e", "1", "2", "--cursor", "page1")
+	assert.Equal(t, 0, code)
+	assert.Equal(t, "/v2/orders/matic/seaport/listings?asset_contract_address=0x8a90cab2b38dba80c64b7734e58ee1db38b8992e&cursor=page1&limit=50&token_ids=1&token_ids=2", (*queries)[0])
+	assert.Contains(t, out, "0xae0b379f8bf426fd2d50c47b7e7f7879cbf56f03c8e65f724c4feee699a4ccf0  0x8d0cf15d459b98fcc84a56d86737f44ff2204751  1 MATIC")
+	assert.Equal(t, "next page: --cursor page2\n", errOut)
+
+	code, out, errOut = runCLI("listings", "--chain", "matic", "--json")
+	assert.Equal(t, 0, code)
+	assert.Contains(t, out, `"next": "page2"`)
+	assert.Equal(t, "", errOut)
+}
+
+func TestUsageErrors(t *testing.T) {
+	fakeAPI(t)
+
+	code, _, errOut := runCLI("bogus")
+	assert.Equal(t, 2, code)
+	assert.Contains(t, errOut, `unknown command "bogus"`)
+
+	code, _, errOut = runCLI("asset", "0x8a90cab2b38dba80c64b7734e58ee1db38b8992e")
+	assert.Equal(t, 2, code)
+	assert.Contains(t, errOut, "usage: opensea asset [flags] <contract> <token_id>")
+
+	code, _, errOut = runCLI("stats", "doodles-official", "--json", "--csv")
+	assert.Equal(t, 2, code)
+	assert.Contains(t, errOut, "choose one of --json, --table and --csv")
+
+	code, _, errOut = runCLI("stats", "doodles-official", "--chain", "solana")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, errOut, `unknown chain "solana"`)
+
+	code, _, errOut = runCLI("collection", "unknown")
+	assert.Equal(t, 1, code)
+	assert.NotEmpty(t, errOut)
+}
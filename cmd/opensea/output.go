@@ -0,0 +1,62 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+type format int
+
+const (
+	formatDefault format = iota // JSON for single objects, a table for lists
+	formatJSON
+	formatTable
+	formatCSV
+)
+
+// result is what a command prints: value as JSON, or columns and rows as a table or CSV.
+type result struct {
+	value   interface{}
+	columns []string
+	rows    [][]string
+	single  bool   // a single object, shown as field/value lines in a table
+	next    string // cursor of the next page
+}
+
+func (r *result) write(w io.Writer, f format) error {
+	if f == formatDefault {
+		f = formatTable
+		if r.single {
+			f = formatJSON
+		}
+	}
+	switch f {
+	case formatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		enc.SetEscapeHTML(false)
+		return enc.Encode(r.value)
+	case formatCSV:
+		cw := csv.NewWriter(w)
+		cw.Write(r.columns)
+		cw.WriteAll(r.rows)
+		return cw.Error()
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if r.single && len(r.rows) == 1 {
+		for i, c := range r.columns {
+			fmt.Fprintf(tw, "%s\t%s\n", strings.ToUpper(c), r.rows[0][i])
+		}
+		return tw.Flush()
+	}
+	fmt.Fprintln(tw, strings.ToUpper(strings.Join(r.columns, "\t")))
+	for _, row := range r.rows {
+		fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,117 @@
+package opensea
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// CoalesceStats counts the GET requests made through a client and how many of them were served by
+// an identical request already in flight rather than by their own.
+type CoalesceStats struct {
+	Requests  int64
+	Coalesced int64
+}
+
+// flightGroup deduplicates concurrent GETs of the same URL: the first caller makes the request and
+// the others wait for its result.
+type flightGroup struct {
+	mu        sync.Mutex
+	calls     map[string]*flight
+	requests  int64
+	coalesced int64
+}
+
+type flight struct {
+	done    chan struct{}
+	body    []byte
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+}
+
+func newFlightGroup() *flightGroup {
+	return &flightGroup{calls: map[string]*flight{}}
+}
+
+// do runs fn once for all concurrent callers of key. fn runs on a context of its own, carrying the
+// values of the first caller's, which is cancelled only once every caller has returned early on its own
+// context; a panic in fn is returned to all of them as an error.
+func (g *flightGroup) do(ctx context.Context, key string, fn func(ctx context.Context) ([]byte, error)) ([]byte, error) {
+	atomic.AddInt64(&g.requests, 1)
+	g.mu.Lock()
+	f, ok := g.calls[key]
+	if ok {
+		atomic.AddInt64(&g.coalesced, 1)
+	} else {
+		fctx, cancel := context.WithCancel(detachedContext{ctx})
+		f = &flight{done: make(chan struct{}), cancel: cancel}
+		g.calls[key] = f
+		go g.run(fctx, key, f, fn)
+	}
+	f.waiters++
+	g.mu.Unlock()
+
+	select {
+	case <-f.done:
+		return f.body, f.err
+	case <-ctx.Done():
+		g.leave(key, f)
+		return nil, ctx.Err()
+	}
+}
+
+func (g *flightGroup) run(ctx context.Context, key string, f *flight, fn func(ctx context.Context) ([]byte, error)) {
+	defer func() {
+		if r := recover(); r != nil {
+			f.body, f.err = nil, fmt.Errorf("opensea: panic in request %s: %v", key, r)
+		}
+		g.mu.Lock()
+		if g.calls[key] == f {
+			delete(g.calls, key)
+		}
+		g.mu.Unlock()
+		f.cancel()
+		close(f.done)
+	}()
+	f.body, f.err = fn(ctx)
+}
+
+// leave drops a caller that stopped waiting, cancelling the request when it was the last one.
+func (g *flightGroup) leave(key string, f *flight) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	f.waiters--
+	if f.waiters > 0 {
+		return
+	}
+	f.cancel()
+	if g.calls[key] == f {
+		delete(g.calls, key)
+	}
+}
+
+// detachedContext keeps the values of a context but neither its deadline nor its cancellation.
+type detachedContext struct{ parent context.Context }
+
+func (detachedContext) Deadline() (time.Time, bool)         { return time.Time{}, false }
+func (detachedContext) Done() <-chan struct{}               { return nil }
+func (detachedContext) Err() error                          { return nil }
+func (c detachedContext) Value(key interface{}) interface{} { return c.parent.Value(key) }
+
+func (g *flightGroup) stats() CoalesceStats {
+	return CoalesceStats{
+		Requests:  atomic.LoadInt64(&g.requests),
+		Coalesced: atomic.LoadInt64(&g.coalesced),
+	}
+}
+
+// CoalesceStats reports how many GETs were deduplicated since the client was created.
+func (o Opensea) CoalesceStats() CoalesceStats {
+	if o.flights == nil {
+		return CoalesceStats{}
+	}
+	return o.flights.stats()
+}
@@ -0,0 +1,183 @@
+package opensea
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// blockingServer holds every request until release is closed.
+func blockingServer(t *testing.T, calls *int32, status int, release chan struct{}) Opensea {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(calls, 1)
+		<-release
+		w.WriteHeader(status)
+		w.Write([]byte(`{"slug": "doodles-official"}`))
+	}))
+	t.Cleanup(srv.Close)
+	return Opensea{API: srv.URL, httpClient: srv.Client(), flights: newFlightGroup()}
+}
+
+func waitRequests(o Opensea, n int64) {
+	for o.CoalesceStats().Requests < n {
+		time.Sleep(time.Millisecond)
+	}
+}
+
+func TestCoalesceIdenticalGets(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+	o := blockingServer(t, &calls, http.StatusOK, release)
+
+	var wg sync.WaitGroup
+	bodies := make([][]byte, 10)
+	for i := range bodies {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			b, err := o.GetPath(context.Background(), "/api/v1/collection/doodles-official")
+			assert.Nil(t, err)
+			bodies[i] = b
+		}(i)
+	}
+	waitRequests(o, 10)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+	assert.Equal(t, CoalesceStats{Requests: 10, Coalesced: 9}, o.CoalesceStats())
+	for _, b := range bodies {
+		assert.Equal(t, `{"slug": "doodles-official"}`, string(b))
+	}
+
+	// once done, the next call makes its own request
+	_, err := o.GetPath(context.Background(), "/api/v1/collection/doodles-official")
+	assert.Nil(t, err)
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestCoalesceSharesErrors(t *testing.T) {
+	var calls int32
+	release := make(chan struct{})
+	o := blockingServer(t, &calls, http.StatusInternalServerError, release)
+
+	errs := make(chan error, 3)
+	for i := 0; i < 3; i++ {
+		go func() {
+			_, err := o.GetPath(context.Background(), "/api/v1/asset_contract/0xabc")
+			errs <- err
+		}()
+	}
+	waitRequests(o, 3)
+	for atomic.LoadInt32(&calls) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// a waiter giving up does not cancel the shared request
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err := o.GetPath(ctx, "/api/v1/asset_contract/0xabc")
+	assert.Equal(t, context.Canceled, err)
+
+	close(release)
+	for i := 0; i < 3; i++ {
+		assert.NotNil(t, <-errs)
+	}
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestCoalesceOutlivesFirstCaller(t *testing.T) {
+	g := newFlightGroup()
+	release := make(chan struct{})
+	fetch := func(ctx context.Context) ([]byte, error) {
+		select {
+		case <-release:
+			return []byte("ok"), nil
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	first, cancelFirst := context.WithCancel(context.Background())
+	errs := make(chan error, 1)
+	go func() {
+		_, err := g.do(first, "k", fetch)
+		errs <- err
+	}()
+	for g.stats().Requests < 1 {
+		time.Sleep(time.Millisecond)
+	}
+	bodies := make(chan string, 1)
+	go func() {
+		b, err := g.do(context.Background(), "k", fetch)
+		assert.Nil(t, err)
+		bodies <- string(b)
+	}()
+	for g.stats().Requests < 2 {
+		time.Sleep(time.Millisecond)
+	}
+
+	// the first caller leaving does not fail the one still waiting
+	cancelFirst()
+	assert.Equal(t, context.Canceled, <-errs)
+	close(release)
+	assert.Equal(t, "ok", <-bodies)
+}
+
+func TestCoalesceCancelsWhenAllCallersLeave(t *testing.T) {
+	g := newFlightGroup()
+	cancelled := make(chan struct{})
+	fetch := func(ctx context.Context) ([]byte, error) {
+		<-ctx.Done()
+		close(cancelled)
+		return nil, ctx.Err()
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	errs := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() {
+			_, err := g.do(ctx, "k", fetch)
+			errs <- err
+		}()
+	}
+	for g.stats().Requests < 2 {
+		time.Sleep(time.Millisecond)
+	}
+	cancel()
+	assert.Equal(t, context.Canceled, <-errs)
+	assert.Equal(t, context.Canceled, <-errs)
+	select {
+	case <-cancelled:
+	case <-time.After(time.Second):
+		t.Fatal("the shared request was not cancelled")
+	}
+}
+
+func TestCoalescePanic(t *testing.T) {
+	g := newFlightGroup()
+	release := make(chan struct{})
+	fetch := func(ctx context.Context) ([]byte, error) {
+		<-release
+		panic("boom")
+	}
+	errs := make(chan error, 3)
+	for i := 0; i < 3; i++ {
+		go func() {
+			_, err := g.do(context.Background(), "k", fetch)
+			errs <- err
+		}()
+	}
+	for g.stats().Requests < 3 {
+		time.Sleep(time.Millisecond)
+	}
+	close(release)
+	for i := 0; i < 3; i++ {
+		assert.EqualError(t, <-errs, "opensea: panic in request k: boom")
+	}
+}
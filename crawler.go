@@ -0,0 +1,173 @@
+package opensea
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+// TokenSnapshot is a token as a Crawler found it: the asset, with its owner, traits and last sale, and
+// its active Seaport listings, which are nil when the crawl skipped listings and empty when there are none.
+type TokenSnapshot struct {
+	Asset     *Asset     `json:"asset" bson:"asset"`
+	Listings  []*OrderV2 `json:"listings" bson:"listings"`
+	CrawledAt TimeNano   `json:"crawled_at" bson:"crawled_at"`
+}
+
+// CrawlSink receives the tokens of a crawl, a page at a time.
+type CrawlSink interface {
+	WriteTokens(ctx context.Context, tokens []*TokenSnapshot) error
+}
+
+// CrawlSinkFunc makes a function a CrawlSink.
+type CrawlSinkFunc func(ctx context.Context, tokens []*TokenSnapshot) error
+
+func (f CrawlSinkFunc) WriteTokens(ctx context.Context, tokens []*TokenSnapshot) error {
+	return f(ctx, tokens)
+}
+
+// JSONLSink writes every token as one line of JSON.
+type JSONLSink struct {
+	enc *json.Encoder
+}
+
+func NewJSONLSink(w io.Writer) *JSONLSink {
+	return &JSONLSink{enc: json.NewEncoder(w)}
+}
+
+func (s *JSONLSink) WriteTokens(ctx context.Context, tokens []*TokenSnapshot) error {
+	for _, t := range tokens {
+		if err := s.enc.Encode(t); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Crawler snapshots every token of a collection, or of a contract. It walks the assets endpoint page
+// by page and adds the listings of the tokens before handing each page to Sink. Every request goes
+// through Client, under its Limiter.
+//
+// With a Store, the crawler saves the cursor of the next page once a page is written, so that a Run
+// that failed is resumed by the next one; a finished crawl clears the cursor and the next Run starts over.
+type Crawler struct {
+	Client     Opensea
+	Collection string // slug of the collection to crawl
+	Contract   string // address of the contract to crawl, when Collection is empty
+	Sink       CrawlSink
+	Key        string // name of the checkpoint in Store
+	Store      CheckpointStore
+	PageSize   int // assets per page, 50 if zero
+
+	SkipListings bool
+	Batch        BatchOptions // of the listings queries
+}
+
+// NewCrawler crawls a collection by slug, or a contract by address.
+func NewCrawler(o Opensea, slugOrContract string, sink CrawlSink) *Crawler {
+	c := &Crawler{Client: o, Sink: sink, Key: slugOrContract}
+	if IsHexAddress(slugOrContract) {
+		c.Contract = slugOrContract
+	} else {
+		c.Collection = slugOrContract
+	}
+	return c
+}
+
+// Run crawls up to the last page and returns the number of tokens it wrote.
+func (c *Crawler) Run(ctx context.Context) (int, error) {
+	if c.Collection == "" && c.Contract == "" {
+		return 0, errors.New("crawler: no collection or contract to crawl")
+	}
+	params := GetAssetsParams{CollectionSlug: c.Collection, AssetContractAddress: c.Contract, Limit: c.PageSize}
+	if params.Limit <= 0 {
+		params.Limit = 50
+	}
+	if c.Store != nil {
+		cp, err := c.Store.Load(ctx, c.Key)
+		if err != nil {
+			return 0, err
+		}
+		if cp != nil {
+			params.Cursor = cp.Cursor
+		}
+	}
+
+	written := 0
+	for {
+		page, err := c.Client.GetAssetsWithContext(ctx, params)
+		if err != nil {
+			return written, err
+		}
+		tokens, err := c.snapshot(ctx, page.Assets)
+		if err != nil {
+			return written, err
+		}
+		if err := c.Sink.WriteTokens(ctx, tokens); err != nil {
+			return written, err
+		}
+		written += len(tokens)
+
+		params.Cursor = page.Next
+		if len(page.Assets) == 0 {
+			params.Cursor = ""
+		}
+		if c.Store != nil {
+			if err := c.Store.Save(ctx, c.Key, Checkpoint{Cursor: params.Cursor}); err != nil {
+				return written, err
+			}
+		}
+		if params.Cursor == "" {
+			return written, nil
+		}
+		if err := ctx.Err(); err != nil {
+			return written, err
+		}
+	}
+}
+
+// snapshot adds the listings to a page of assets. A token whose listings could not be fetched fails
+// the page, to be crawled again.
+func (c *Crawler) snapshot(ctx context.Context, assets []Asset) ([]*TokenSnapshot, error) {
+	now := TimeNano(time.Now().UTC())
+	tokens := make([]*TokenSnapshot, len(assets))
+	var contracts []string
+	ids := map[string][]string{}
+	for i := range assets {
+		a := &assets[i]
+		tokens[i] = &TokenSnapshot{Asset: a, CrawledAt: now}
+		if a.AssetContract == nil {
+			continue
+		}
+		addr := a.AssetContract.Address.String()
+		if _, ok := ids[addr]; !ok {
+			contracts = append(contracts, addr)
+		}
+		ids[addr] = append(ids[addr], a.TokenID)
+	}
+	if c.SkipListings {
+		return tokens, nil
+	}
+
+	listings := map[string][]*OrderV2{}
+	for _, addr := range contracts {
+		for _, tl := range c.Client.GetActiveListingsBatch(ctx, addr, ids[addr], c.Batch) {
+			if tl.Err != nil {
+				return nil, fmt.Errorf("crawler: listings of %s/%s: %w", addr, tl.TokenID, tl.Err)
+			}
+			listings[addr+"/"+tl.TokenID] = tl.Orders
+		}
+	}
+	for _, t := range tokens {
+		if t.Asset.AssetContract != nil {
+			t.Listings = listings[t.Asset.AssetContract.Address.String()+"/"+t.Asset.TokenID]
+		}
+		if t.Listings == nil {
+			t.Listings = []*OrderV2{}
+		}
+	}
+	return tokens, nil
+}
@@ -0,0 +1,131 @@
+package opensea
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const pandas = "0x9bfa45382268e4bacbd1175395728153dc5248f2"
+
+// crawlServer serves two pages of assets, and the listing of test-files/listings-v2.json for token 1998.
+func crawlServer(t *testing.T, queries *[]string) *httptest.Server {
+	listing, err := ioutil.ReadFile("test-files/listings-v2.json")
+	assert.Nil(t, err)
+	asset := func(id string) string {
+		return fmt.Sprintf(`{"token_id": %q, "asset_contract": {"address": %q}, "collection": {"slug": "drunken-pandas-official"},
+			"owner": {"address": "0x8d0cf15d459b98fcc84a56d86737f44ff2204751"}, "traits": [{"trait_type": "Hat", "value": "Cap"}],
+			"last_sale": {"event_type": "successful", "total_price": "500000000000000000", "payment_token": {"symbol": "ETH", "decimals": 18}}}`, id, pandas)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		*queries = append(*queries, r.URL.Path+"?"+r.URL.RawQuery)
+		q := r.URL.Query()
+		switch r.URL.Path {
+		case "/api/v1/assets":
+			assert.Equal(t, "drunken-pandas-official", q.Get("collection_slug"))
+			if q.Get("cursor") == "" {
+				fmt.Fprintf(w, `{"next": "page2", "assets": [%s, %s]}`, asset("1998"), asset("1999"))
+			} else {
+				fmt.Fprintf(w, `{"assets": [%s]}`, asset("2000"))
+			}
+		case "/v2/orders/ethereum/seaport/listings":
+			orders := []json.RawMessage{}
+			if strings.Contains(r.URL.RawQuery, "token_ids=1998") {
+				orders = append(orders, listing)
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{"orders": orders})
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestCrawler(t *testing.T) {
+	var queries []string
+	o := testClient(crawlServer(t, &queries))
+	o.Chain = ChainEthereum
+
+	var buf bytes.Buffer
+	c := NewCrawler(o, "drunken-pandas-official", NewJSONLSink(&buf))
+	n, err := c.Run(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, 3, n)
+	assert.Equal(t, []string{
+		"/api/v1/assets?collection_slug=drunken-pandas-official&limit=50",
+		"/v2/orders/ethereum/seaport/listings?asset_contract_address=" + pandas + "&limit=50&token_ids=1998&token_ids=1999",
+		"/api/v1/assets?collection_slug=drunken-pandas-official&cursor=page2&limit=50",
+		"/v2/orders/ethereum/seaport/listings?asset_contract_address=" + pandas + "&limit=50&token_ids=2000",
+	}, queries)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	assert.Len(t, lines, 3)
+	var first TokenSnapshot
+	assert.Nil(t, json.Unmarshal([]byte(lines[0]), &first))
+	assert.Equal(t, "1998", first.Asset.TokenID)
+	assert.Equal(t, Address("0x8d0cf15d459b98fcc84a56d86737f44ff2204751"), first.Asset.Owner.Address)
+	assert.Equal(t, "Cap", first.Asset.Traits[0].ValueString())
+	assert.Equal(t, "0.5", first.Asset.LastSale.TotalPrice.Units())
+	if assert.Len(t, first.Listings, 1) {
+		assert.Equal(t, "0xae0b379f8bf426fd2d50c47b7e7f7879cbf56f03c8e65f724c4feee699a4ccf0", first.Listings[0].OrderHash)
+	}
+	assert.False(t, first.CrawledAt.IsZero())
+	assert.Contains(t, lines[1], `"listings":[]`, "fetched, none found")
+}
+
+func TestCrawlerResumes(t *testing.T) {
+	var queries []string
+	o := testClient(crawlServer(t, &queries))
+	o.Chain = ChainEthereum
+	store := NewMemoryCheckpointStore()
+
+	var ids []string
+	fail := errors.New("disk full")
+	sink := CrawlSinkFunc(func(ctx context.Context, tokens []*TokenSnapshot) error {
+		if tokens[0].Asset.TokenID == "2000" && fail != nil {
+			return fail
+		}
+		for _, t := range tokens {
+			ids = append(ids, t.Asset.TokenID)
+		}
+		return nil
+	})
+	c := NewCrawler(o, "drunken-pandas-official", sink)
+	c.Store, c.SkipListings = store, true
+
+	n, err := c.Run(context.Background())
+	assert.Equal(t, fail, err)
+	assert.Equal(t, 2, n)
+	cp, _ := store.Load(context.Background(), "drunken-pandas-official")
+	assert.Equal(t, "page2", cp.Cursor)
+
+	fail, queries = nil, nil
+	n, err = c.Run(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, []string{"/api/v1/assets?collection_slug=drunken-pandas-official&cursor=page2&limit=50"}, queries)
+	assert.Equal(t, []string{"1998", "1999", "2000"}, ids)
+
+	// A finished crawl starts over.
+	cp, _ = store.Load(context.Background(), "drunken-pandas-official")
+	assert.Equal(t, "", cp.Cursor)
+}
+
+func TestNewCrawler(t *testing.T) {
+	c := NewCrawler(Opensea{}, "0x9BFA45382268E4BACBD1175395728153DC5248F2", nil)
+	assert.Equal(t, "0x9BFA45382268E4BACBD1175395728153DC5248F2", c.Contract)
+	assert.Equal(t, "", c.Collection)
+
+	_, err := (&Crawler{}).Run(context.Background())
+	assert.EqualError(t, err, "crawler: no collection or contract to crawl")
+}
@@ -9,7 +9,7 @@ import (
 
 type AssetEventsResponse struct {
 	Next        string   `json:"next" bson:"next"`
-	Previous    string   `json:"previous" bson:"next"`
+	Previous    string   `json:"previous" bson:"previous"`
 	AssetEvents []*Event `json:"asset_events" bson:"asset_events"`
 }
 
@@ -30,29 +30,53 @@ type Event struct {
 	CreatedDate         TimeNano            `json:"created_date" bson:"created_date"`
 	ModifiedDate        TimeNano            `json:"modified_date" bson:"modified_date"`
 	ContractAddress     Address             `json:"contract_address" bson:"contract_address"`
-	LogIndex            interface{}         `json:"log_index" bson:"log_index"`
+	LogIndex            Number              `json:"log_index" bson:"log_index"`
 	EventType           EventType           `json:"event_type" bson:"event_type"`
 	AuctionType         string              `json:"auction_type" bson:"auction_type"`
-	StartingPrice       string              `json:"starting_price" bson:"starting_price"`
-	EndingPrice         string              `json:"ending_price" bson:"ending_price"`
+	StartingPrice       Amount              `json:"starting_price" bson:"starting_price"`
+	EndingPrice         Amount              `json:"ending_price" bson:"ending_price"`
 	Duration            string              `json:"duration" bson:"duration"`
-	MinPrice            Number              `json:"min_price" bson:"min_price"`
-	OfferedTo           Number              `json:"offered_to" bson:"offered_to"`
-	BidAmount           Number              `json:"bid_amount" bson:"bid_amount"`
-	TotalPrice          Number              `json:"total_price" bson:"total_price"`
-	CustomEventName     interface{}         `json:"custom_event_name" bson:"custom_event_name"`
+	MinPrice            Amount              `json:"min_price" bson:"min_price"`
+	OfferedTo           Amount              `json:"offered_to" bson:"offered_to"`
+	BidAmount           Amount              `json:"bid_amount" bson:"bid_amount"`
+	TotalPrice          Amount              `json:"total_price" bson:"total_price"`
+	CustomEventName     string              `json:"custom_event_name" bson:"custom_event_name"`
 	Quantity            string              `json:"quantity" bson:"quantity"`
-	PayoutAmount        interface{}         `json:"payout_amount" bson:"payout_amount"`
+	PayoutAmount        Amount              `json:"payout_amount" bson:"payout_amount"`
 	EventTimestamp      TimeNano            `json:"event_timestamp" bson:"event_timestamp"`
 	Relayer             string              `json:"relayer" bson:"relayer"`
 	Collection          uint64              `json:"collection" bson:"collection"`
-	PayoutAccount       interface{}         `json:"payout_account" bson:"payout_account"`
-	PayoutAssetContract interface{}         `json:"payout_asset_contract" bson:"payout_asset_contract"`
-	PayoutCollection    interface{}         `json:"payout_collection" bson:"payout_collection"`
+	PayoutAccount       *Account            `json:"payout_account" bson:"payout_account"`
+	PayoutAssetContract *AssetContract      `json:"payout_asset_contract" bson:"payout_asset_contract"`
+	PayoutCollection    *Collection         `json:"payout_collection" bson:"payout_collection"`
 	BuyOrder            uint64              `json:"buy_order" bson:"buy_order"`
 	SellOrder           uint64              `json:"sell_order" bson:"sell_order"`
-	ListingTime         string              `json:"listing_time" bson:"listing_time"`
+	ListingTime         TimeNano            `json:"listing_time" bson:"listing_time"`
 	IsPrivate           bool                `json:"is_private" bson:"is_private"`
+	OrderType           OrderKind           `json:"order_type" bson:"order_type"`
+	OrderHash           string              `json:"order_hash" bson:"order_hash"`
+	Maker               *Account            `json:"maker" bson:"maker"`
+	Taker               *Account            `json:"taker" bson:"taker"`
+	Buyer               *Account            `json:"buyer" bson:"buyer"`
+	Criteria            *OfferCriteria      `json:"criteria" bson:"criteria"`
+}
+
+// UnmarshalJSON labels the prices of the event with the decimals of its payment token.
+func (e *Event) UnmarshalJSON(b []byte) error {
+	type event Event
+	if err := json.Unmarshal(b, (*event)(e)); err != nil {
+		return err
+	}
+	if e.PaymentToken != nil && e.PaymentToken.Decimals > 0 {
+		d := int(e.PaymentToken.Decimals)
+		for _, v := range []*Amount{&e.StartingPrice, &e.EndingPrice, &e.MinPrice, &e.OfferedTo, &e.BidAmount, &e.TotalPrice, &e.PayoutAmount} {
+			*v = v.WithDecimals(d)
+		}
+	}
+	if fee := e.DevFeePaymentEvent; fee != nil && fee.PaymentToken.Decimals > 0 {
+		fee.TotalPrice = fee.TotalPrice.WithDecimals(int(fee.PaymentToken.Decimals))
+	}
+	return nil
 }
 
 func (e Event) IsBundle() bool {
@@ -60,13 +84,13 @@ func (e Event) IsBundle() bool {
 }
 
 type PaymentToken struct {
-	Symbol   string      `json:"symbol" bson:"symbol"`
-	Address  Address     `json:"address" bson:"address"`
-	ImageURL string      `json:"image_url" bson:"image_url"`
-	Name     string      `json:"name" bson:"name"`
-	Decimals int64       `json:"decimals" bson:"decimals"`
-	EthPrice interface{} `json:"eth_price" bson:"eth_price"`
-	UsdPrice interface{} `json:"usd_price" bson:"usd_price"`
+	Symbol   string  `json:"symbol" bson:"symbol"`
+	Address  Address `json:"address" bson:"address"`
+	ImageURL string  `json:"image_url" bson:"image_url"`
+	Name     string  `json:"name" bson:"name"`
+	Decimals int64   `json:"decimals" bson:"decimals"`
+	EthPrice Decimal `json:"eth_price" bson:"eth_price"`
+	UsdPrice Decimal `json:"usd_price" bson:"usd_price"`
 }
 
 type Transaction struct {
@@ -79,15 +103,15 @@ type Transaction struct {
 	TransactionIndex string   `json:"transaction_index" bson:"transaction_index"`
 	BlockNumber      string   `json:"block_number" bson:"block_number"`
 	BlockHash        string   `json:"block_hash" bson:"block_hash"`
-	Timestamp        string   `json:"timestamp" bson:"timestamp"`
+	Timestamp        TimeNano `json:"timestamp" bson:"timestamp"`
 }
 
 // DevFeePaymentEvent is fee transfer event from OpenSea to Dev, It appears to be running in bulk on a regular basis.
 type DevFeePaymentEvent struct {
 	EventType      string       `json:"event_type" bson:"event_type"`
-	EventTimestamp string       `json:"event_timestamp" bson:"event_timestamp"`
-	AuctionType    interface{}  `json:"auction_type" bson:"auction_type"`
-	TotalPrice     interface{}  `json:"total_price" bson:"total_price"`
+	EventTimestamp TimeNano     `json:"event_timestamp" bson:"event_timestamp"`
+	AuctionType    AuctionType  `json:"auction_type" bson:"auction_type"`
+	TotalPrice     Amount       `json:"total_price" bson:"total_price"`
 	Transaction    Transaction  `json:"transaction" bson:"transaction"`
 	PaymentToken   PaymentToken `json:"payment_token" bson:"payment_token"`
 }
@@ -104,6 +128,36 @@ const (
 	EventTypeTransfer           EventType = "transfer"
 	EventTypeApprove            EventType = "approve"
 	EventTypeCompositionCreated EventType = "composition_created"
+	EventTypeOfferEntered       EventType = "offer_entered"
+	EventTypeCustom             EventType = "custom"
+	EventTypePayout             EventType = "payout"
+
+	// event types of the newer events API, where listings and offers are all "order" events told apart by OrderType
+	EventTypeSale       EventType = "sale"
+	EventTypeOrder      EventType = "order"
+	EventTypeCancel     EventType = "cancel"
+	EventTypeRedemption EventType = "redemption"
+
+	// event types pushed by the stream API
+	EventTypeItemListed          EventType = "item_listed"
+	EventTypeItemSold            EventType = "item_sold"
+	EventTypeItemTransferred     EventType = "item_transferred"
+	EventTypeItemCancelled       EventType = "item_cancelled"
+	EventTypeItemMetadataUpdated EventType = "item_metadata_updated"
+	EventTypeItemReceivedOffer   EventType = "item_received_offer"
+	EventTypeItemReceivedBid     EventType = "item_received_bid"
+	EventTypeCollectionOffer     EventType = "collection_offer"
+	EventTypeTraitOffer          EventType = "trait_offer"
+)
+
+type OrderKind string
+
+const (
+	OrderKindNone            OrderKind = ""
+	OrderKindListing         OrderKind = "listing"
+	OrderKindItemOffer       OrderKind = "item_offer"
+	OrderKindCollectionOffer OrderKind = "collection_offer"
+	OrderKindTraitOffer      OrderKind = "trait_offer"
 )
 
 type AuctionType string
@@ -182,31 +236,56 @@ func (o Opensea) RetrievingEvents(params *EventParams) ([]*Event, error) {
 }
 
 func (o Opensea) RetrievingEventsWithContext(ctx context.Context, params *EventParams) (events []*Event, err error) {
+	events = []*Event{}
+	err = o.WalkEvents(ctx, params, func(e *Event) error {
+		events = append(events, e)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return
+}
+
+// WalkEvents calls fn with the events of every page in turn, without keeping them, so that long
+// histories can be processed in constant memory. It stops at the first error of fn.
+func (o Opensea) WalkEvents(ctx context.Context, params *EventParams, fn func(*Event) error) error {
 	if params == nil {
 		params = NewRetrievingEventsParams()
 	}
-
-	events = []*Event{}
-	for true {
-		path := "/api/v1/events?" + params.Encode()
-		b, err := o.GetPath(ctx, path)
+	for {
+		eventsResp, err := o.RetrievingEventsPage(ctx, params)
 		if err != nil {
-			return nil, err
+			return err
 		}
-		//fmt.Println(";;;",string(b))
-		var eventsResp AssetEventsResponse
-		err = json.Unmarshal(b, &eventsResp)
-		if err != nil {
-			return nil, err
+		for _, e := range eventsResp.AssetEvents {
+			if err := fn(e); err != nil {
+				return err
+			}
 		}
-		//fmt.Println("events:", len(eventsResp.AssetEvents), path)
-
-		events = append(events, eventsResp.AssetEvents...)
 		if eventsResp.Next == "" {
-			break
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
 		}
 		params.Cursor = eventsResp.Next
 	}
+}
 
-	return
+// RetrievingEventsPage fetches the single page of events at params.Cursor.
+func (o Opensea) RetrievingEventsPage(ctx context.Context, params *EventParams) (*AssetEventsResponse, error) {
+	if params == nil {
+		params = NewRetrievingEventsParams()
+	}
+	path := "/api/v1/events?" + params.Encode()
+	b, err := o.GetPath(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	var eventsResp AssetEventsResponse
+	if err := json.Unmarshal(b, &eventsResp); err != nil {
+		return nil, err
+	}
+	return &eventsResp, nil
 }
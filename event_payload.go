@@ -0,0 +1,318 @@
+package opensea
+
+import (
+	"strconv"
+)
+
+type EventKind int
+
+const (
+	EventKindUnknown EventKind = iota
+	EventKindListing
+	EventKindSale
+	EventKindTransfer
+	EventKindCancel
+	EventKindOffer
+	EventKindOfferWithdrawn
+	EventKindCollectionOffer
+	EventKindTraitOffer
+	EventKindApproval
+	EventKindRedemption
+	EventKindMetadataUpdate
+	EventKindPayout
+	EventKindCustom
+)
+
+var eventKindNames = map[EventKind]string{
+	EventKindUnknown:         "unknown",
+	EventKindListing:         "listing",
+	EventKindSale:            "sale",
+	EventKindTransfer:        "transfer",
+	EventKindCancel:          "cancel",
+	EventKindOffer:           "offer",
+	EventKindOfferWithdrawn:  "offer_withdrawn",
+	EventKindCollectionOffer: "collection_offer",
+	EventKindTraitOffer:      "trait_offer",
+	EventKindApproval:        "approval",
+	EventKindRedemption:      "redemption",
+	EventKindMetadataUpdate:  "metadata_update",
+	EventKindPayout:          "payout",
+	EventKindCustom:          "custom",
+}
+
+func (k EventKind) String() string {
+	if s, ok := eventKindNames[k]; ok {
+		return s
+	}
+	return "kind(" + strconv.Itoa(int(k)) + ")"
+}
+
+// Kind folds the event types of the v1 events API, the newer events API and the stream API
+// into one set of kinds.
+func (e Event) Kind() EventKind {
+	switch e.EventType {
+	case EventTypeCreated, EventTypeItemListed:
+		return EventKindListing
+	case EventTypeSuccessful, EventTypeSale, EventTypeItemSold:
+		return EventKindSale
+	case EventTypeTransfer, EventTypeItemTransferred:
+		return EventKindTransfer
+	case EventTypeCancelled, EventTypeCancel, EventTypeItemCancelled:
+		return EventKindCancel
+	case EventTypeBidEntered, EventTypeOfferEntered, EventTypeItemReceivedBid, EventTypeItemReceivedOffer:
+		return EventKindOffer
+	case EventTypeBidWithdrawn:
+		return EventKindOfferWithdrawn
+	case EventTypeCollectionOffer:
+		return EventKindCollectionOffer
+	case EventTypeTraitOffer:
+		return EventKindTraitOffer
+	case EventTypeApprove:
+		return EventKindApproval
+	case EventTypeRedemption:
+		return EventKindRedemption
+	case EventTypeItemMetadataUpdated:
+		return EventKindMetadataUpdate
+	case EventTypePayout:
+		return EventKindPayout
+	case EventTypeCustom:
+		return EventKindCustom
+	case EventTypeOrder:
+		switch e.OrderType {
+		case OrderKindListing:
+			return EventKindListing
+		case OrderKindItemOffer:
+			return EventKindOffer
+		case OrderKindCollectionOffer:
+			return EventKindCollectionOffer
+		case OrderKindTraitOffer:
+			return EventKindTraitOffer
+		}
+	}
+	return EventKindUnknown
+}
+
+// EventPayload is implemented by the typed views of an Event returned by Event.Payload.
+type EventPayload interface {
+	Kind() EventKind
+}
+
+type ListingPayload struct {
+	Asset         *Asset
+	AssetBundle   *AssetBundle
+	Seller        *Account
+	AuctionType   AuctionType
+	StartingPrice Amount
+	EndingPrice   Amount
+	Duration      string
+	PaymentToken  *PaymentToken
+	Quantity      string
+	OrderHash     string
+	IsPrivate     bool
+}
+
+type SalePayload struct {
+	Asset        *Asset
+	AssetBundle  *AssetBundle
+	Seller       *Account
+	Buyer        *Account
+	TotalPrice   Amount
+	PaymentToken *PaymentToken
+	Quantity     string
+	OrderHash    string
+	Transaction  *Transaction
+	IsPrivate    bool
+}
+
+type TransferPayload struct {
+	Asset       *Asset
+	AssetBundle *AssetBundle
+	From        *Account
+	To          *Account
+	Quantity    string
+	Transaction *Transaction
+}
+
+type CancelPayload struct {
+	Asset       *Asset
+	AssetBundle *AssetBundle
+	Maker       *Account
+	OrderHash   string
+	Transaction *Transaction
+}
+
+// OfferPayload covers offers on an item, on a whole collection and on a trait;
+// Criteria is set for the latter two and Asset usually is not.
+type OfferPayload struct {
+	kind         EventKind
+	Asset        *Asset
+	Maker        *Account
+	Amount       Amount
+	PaymentToken *PaymentToken
+	Quantity     string
+	OrderHash    string
+	Criteria     *OfferCriteria
+}
+
+type OfferWithdrawnPayload struct {
+	Asset        *Asset
+	Maker        *Account
+	Amount       Amount
+	PaymentToken *PaymentToken
+}
+
+type ApprovalPayload struct {
+	Asset    *Asset
+	Owner    *Account
+	Approved *Account
+}
+
+type RedemptionPayload struct {
+	Asset       *Asset
+	From        *Account
+	To          *Account
+	Transaction *Transaction
+}
+
+type MetadataUpdatePayload struct {
+	Asset *Asset
+}
+
+type PayoutPayload struct {
+	Account       *Account
+	Amount        Amount
+	AssetContract *AssetContract
+	Collection    *Collection
+	PaymentToken  *PaymentToken
+	Transaction   *Transaction
+}
+
+type CustomPayload struct {
+	Asset *Asset
+	Name  string
+}
+
+type OfferCriteria struct {
+	Collection *struct {
+		Slug string `json:"slug" bson:"slug"`
+	} `json:"collection" bson:"collection"`
+	Contract *struct {
+		Address Address `json:"address" bson:"address"`
+	} `json:"contract" bson:"contract"`
+	Trait *struct {
+		Type  string `json:"type" bson:"type"`
+		Value string `json:"value" bson:"value"`
+	} `json:"trait" bson:"trait"`
+}
+
+func (ListingPayload) Kind() EventKind        { return EventKindListing }
+func (SalePayload) Kind() EventKind           { return EventKindSale }
+func (TransferPayload) Kind() EventKind       { return EventKindTransfer }
+func (CancelPayload) Kind() EventKind         { return EventKindCancel }
+func (p OfferPayload) Kind() EventKind        { return p.kind }
+func (OfferWithdrawnPayload) Kind() EventKind { return EventKindOfferWithdrawn }
+func (ApprovalPayload) Kind() EventKind       { return EventKindApproval }
+func (RedemptionPayload) Kind() EventKind     { return EventKindRedemption }
+func (MetadataUpdatePayload) Kind() EventKind { return EventKindMetadataUpdate }
+func (PayoutPayload) Kind() EventKind         { return EventKindPayout }
+func (CustomPayload) Kind() EventKind         { return EventKindCustom }
+
+// Payload returns the typed view of the event for its Kind, or nil when the kind is unknown.
+func (e Event) Payload() EventPayload {
+	kind := e.Kind()
+	switch kind {
+	case EventKindListing:
+		return &ListingPayload{
+			Asset:         e.Asset,
+			AssetBundle:   e.AssetBundle,
+			Seller:        firstAccount(e.Maker, e.Seller, e.FromAccount),
+			AuctionType:   AuctionType(e.AuctionType),
+			StartingPrice: e.StartingPrice,
+			EndingPrice:   e.EndingPrice,
+			Duration:      e.Duration,
+			PaymentToken:  e.PaymentToken,
+			Quantity:      e.Quantity,
+			OrderHash:     e.OrderHash,
+			IsPrivate:     e.IsPrivate,
+		}
+	case EventKindSale:
+		return &SalePayload{
+			Asset:        e.Asset,
+			AssetBundle:  e.AssetBundle,
+			Seller:       firstAccount(e.Seller, e.Maker),
+			Buyer:        firstAccount(e.WinnerAccount, e.Buyer, e.Taker),
+			TotalPrice:   e.TotalPrice,
+			PaymentToken: e.PaymentToken,
+			Quantity:     e.Quantity,
+			OrderHash:    e.OrderHash,
+			Transaction:  e.Transaction,
+			IsPrivate:    e.IsPrivate,
+		}
+	case EventKindTransfer:
+		return &TransferPayload{
+			Asset:       e.Asset,
+			AssetBundle: e.AssetBundle,
+			From:        e.FromAccount,
+			To:          e.ToAccount,
+			Quantity:    e.Quantity,
+			Transaction: e.Transaction,
+		}
+	case EventKindCancel:
+		return &CancelPayload{
+			Asset:       e.Asset,
+			AssetBundle: e.AssetBundle,
+			Maker:       firstAccount(e.Maker, e.Seller, e.FromAccount),
+			OrderHash:   e.OrderHash,
+			Transaction: e.Transaction,
+		}
+	case EventKindOffer, EventKindCollectionOffer, EventKindTraitOffer:
+		amount := e.BidAmount
+		if amount.Int == nil {
+			amount = e.TotalPrice
+		}
+		return &OfferPayload{
+			kind:         kind,
+			Asset:        e.Asset,
+			Maker:        firstAccount(e.Maker, e.FromAccount),
+			Amount:       amount,
+			PaymentToken: e.PaymentToken,
+			Quantity:     e.Quantity,
+			OrderHash:    e.OrderHash,
+			Criteria:     e.Criteria,
+		}
+	case EventKindOfferWithdrawn:
+		return &OfferWithdrawnPayload{
+			Asset:        e.Asset,
+			Maker:        firstAccount(e.Maker, e.FromAccount),
+			Amount:       e.TotalPrice,
+			PaymentToken: e.PaymentToken,
+		}
+	case EventKindApproval:
+		return &ApprovalPayload{Asset: e.Asset, Owner: e.OwnerAccount, Approved: e.ApprovedAccount}
+	case EventKindRedemption:
+		return &RedemptionPayload{Asset: e.Asset, From: e.FromAccount, To: e.ToAccount, Transaction: e.Transaction}
+	case EventKindMetadataUpdate:
+		return &MetadataUpdatePayload{Asset: e.Asset}
+	case EventKindPayout:
+		return &PayoutPayload{
+			Account:       e.PayoutAccount,
+			Amount:        e.PayoutAmount,
+			AssetContract: e.PayoutAssetContract,
+			Collection:    e.PayoutCollection,
+			PaymentToken:  e.PaymentToken,
+			Transaction:   e.Transaction,
+		}
+	case EventKindCustom:
+		return &CustomPayload{Asset: e.Asset, Name: e.CustomEventName}
+	}
+	return nil
+}
+
+func firstAccount(accounts ...*Account) *Account {
+	for _, v := range accounts {
+		if v != nil {
+			return v
+		}
+	}
+	return nil
+}
@@ -0,0 +1,80 @@
+package opensea
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEventPayloadFixture(t *testing.T) {
+	b, err := ioutil.ReadFile("test-files/opensea-events.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var resp AssetEventsResponse
+	if err := json.Unmarshal(b, &resp); err != nil {
+		t.Fatal(err)
+	}
+	for _, e := range resp.AssetEvents {
+		assert.Equal(t, EventKindSale, e.Kind())
+		sale, ok := e.Payload().(*SalePayload)
+		assert.True(t, ok)
+		assert.Equal(t, "MaFriends", sale.Seller.User.Username)
+		assert.NotNil(t, sale.Buyer)
+		assert.NotNil(t, sale.PaymentToken)
+	}
+}
+
+func TestEventKind(t *testing.T) {
+	cases := map[EventKind]Event{
+		EventKindListing:         {EventType: EventTypeOrder, OrderType: OrderKindListing},
+		EventKindOffer:           {EventType: EventTypeItemReceivedBid},
+		EventKindCollectionOffer: {EventType: EventTypeOrder, OrderType: OrderKindCollectionOffer},
+		EventKindTraitOffer:      {EventType: EventTypeTraitOffer},
+		EventKindSale:            {EventType: EventTypeSale},
+		EventKindCancel:          {EventType: EventTypeCancel},
+		EventKindRedemption:      {EventType: EventTypeRedemption},
+		EventKindUnknown:         {EventType: EventTypeOrder},
+	}
+	for kind, e := range cases {
+		assert.Equal(t, kind, e.Kind(), kind.String())
+	}
+}
+
+func TestEventPayloadNewerEvents(t *testing.T) {
+	str := `{"event_type": "order", "order_type": "trait_offer", "order_hash": "0xabc",
+		"maker": "0x8D0cF15d459b98fcC84A56d86737F44FF2204751", "bid_amount": "1000000000000000000",
+		"criteria": {"collection": {"slug": "doodles-official"}, "trait": {"type": "head", "value": "gold"}}}`
+	var e Event
+	if err := json.Unmarshal([]byte(str), &e); err != nil {
+		t.Fatal(err)
+	}
+	offer, ok := e.Payload().(*OfferPayload)
+	assert.True(t, ok)
+	assert.Equal(t, EventKindTraitOffer, offer.Kind())
+	assert.Equal(t, Address("0x8d0cf15d459b98fcc84a56d86737f44ff2204751"), offer.Maker.Address)
+	assert.Equal(t, "1000000000000000000", offer.Amount.Big().String())
+	assert.Equal(t, "gold", offer.Criteria.Trait.Value)
+	assert.Equal(t, "doodles-official", offer.Criteria.Collection.Slug)
+}
+
+func TestEventPayloadPayout(t *testing.T) {
+	str := `{"event_type": "payout", "payout_amount": 125000000000000000, "custom_event_name": null,
+		"payout_account": {"user": {"username": "dev"}, "address": "0xbc0c08bc6c8e949d54db31eddc8ee809ef323ec3"}}`
+	var e Event
+	if err := json.Unmarshal([]byte(str), &e); err != nil {
+		t.Fatal(err)
+	}
+	payout, ok := e.Payload().(*PayoutPayload)
+	assert.True(t, ok)
+	assert.Equal(t, "0.125", payout.Amount.Units())
+	assert.Equal(t, "dev", payout.Account.User.Username)
+	assert.Nil(t, payout.Collection)
+
+	e = Event{EventType: EventTypeCustom, CustomEventName: "burn"}
+	custom, ok := e.Payload().(*CustomPayload)
+	assert.True(t, ok)
+	assert.Equal(t, "burn", custom.Name)
+}
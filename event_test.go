@@ -1,8 +1,15 @@
 package opensea
 
 import (
+	"context"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestRetrievingEvents(t *testing.T) {
@@ -26,3 +33,52 @@ func TestRetrievingEvents(t *testing.T) {
 		fmt.Println("===>:", v.Asset.TokenID, v.EventType, v.AuctionType, v.StartingPrice, v.EndingPrice, v.CreatedDate.Time().Unix(), v.ListingTime)
 	}
 }
+
+func TestRetrievingEventsStopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		cancel()
+		w.Write([]byte(`{"next": "cursor", "asset_events": [{"event_type": "transfer"}]}`))
+	}))
+	defer srv.Close()
+	o := Opensea{API: srv.URL, httpClient: srv.Client()}
+
+	_, err := o.RetrievingEventsWithContext(ctx, nil)
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+}
+
+func TestWalkEvents(t *testing.T) {
+	var cursors []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		cursors = append(cursors, r.URL.Query().Get("cursor"))
+		if r.URL.Query().Get("cursor") == "" {
+			w.Write([]byte(`{"next": "page2", "asset_events": [{"id": 1}, {"id": 2}]}`))
+			return
+		}
+		w.Write([]byte(`{"asset_events": [{"id": 3}]}`))
+	}))
+	defer srv.Close()
+	o := testClient(srv)
+
+	var ids []uint64
+	err := o.WalkEvents(context.Background(), nil, func(e *Event) error {
+		ids = append(ids, e.ID)
+		return nil
+	})
+	assert.Nil(t, err)
+	assert.Equal(t, []uint64{1, 2, 3}, ids)
+	assert.Equal(t, []string{"", "page2"}, cursors)
+
+	stop := errors.New("stop")
+	ids, cursors = nil, nil
+	err = o.WalkEvents(context.Background(), nil, func(e *Event) error {
+		ids = append(ids, e.ID)
+		return stop
+	})
+	assert.Equal(t, stop, err)
+	assert.Equal(t, []uint64{1}, ids)
+	assert.Len(t, cursors, 1)
+}
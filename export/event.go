@@ -0,0 +1,123 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/jumpblock/go-opensea"
+)
+
+// EventRecord is the row of an event. Price is in base units of the payment token, PriceEth and
+// PriceUsd convert it at the token prices OpenSea sent with the event.
+type EventRecord struct {
+	ID                  int64    `parquet:"name=id, type=INT64"`
+	EventType           string   `parquet:"name=event_type, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	Timestamp           *int64   `parquet:"name=timestamp, type=INT64, convertedtype=TIMESTAMP_MILLIS, repetitiontype=OPTIONAL"`
+	CreatedDate         *int64   `parquet:"name=created_date, type=INT64, convertedtype=TIMESTAMP_MILLIS, repetitiontype=OPTIONAL"`
+	CollectionSlug      string   `parquet:"name=collection_slug, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	ContractAddress     string   `parquet:"name=contract_address, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	TokenID             string   `parquet:"name=token_id, type=BYTE_ARRAY, convertedtype=UTF8"`
+	AssetName           string   `parquet:"name=asset_name, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Quantity            string   `parquet:"name=quantity, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Seller              string   `parquet:"name=seller, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Buyer               string   `parquet:"name=buyer, type=BYTE_ARRAY, convertedtype=UTF8"`
+	FromAddress         string   `parquet:"name=from_address, type=BYTE_ARRAY, convertedtype=UTF8"`
+	ToAddress           string   `parquet:"name=to_address, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Price               *string  `parquet:"name=price, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL"`
+	PriceDecimals       int32    `parquet:"name=price_decimals, type=INT32"`
+	PriceEth            *float64 `parquet:"name=price_eth, type=DOUBLE, repetitiontype=OPTIONAL"`
+	PriceUsd            *float64 `parquet:"name=price_usd, type=DOUBLE, repetitiontype=OPTIONAL"`
+	PaymentTokenSymbol  string   `parquet:"name=payment_token_symbol, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	PaymentTokenAddress string   `parquet:"name=payment_token_address, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	TransactionHash     string   `parquet:"name=transaction_hash, type=BYTE_ARRAY, convertedtype=UTF8"`
+	BlockNumber         *int64   `parquet:"name=block_number, type=INT64, repetitiontype=OPTIONAL"`
+	AuctionType         string   `parquet:"name=auction_type, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	IsPrivate           bool     `parquet:"name=is_private, type=BOOLEAN"`
+}
+
+// NewEventRecord flattens e. Timestamp is Event.OccurredAt; the price is the total price of sales,
+// the bid of offers and the starting price of listings.
+func NewEventRecord(e *opensea.Event) EventRecord {
+	r := EventRecord{
+		ID:              int64(e.ID),
+		EventType:       string(e.EventType),
+		Timestamp:       millis(opensea.TimeNano(e.OccurredAt())),
+		CreatedDate:     millis(e.CreatedDate),
+		CollectionSlug:  e.CollectionSlug,
+		ContractAddress: e.ContractAddress.String(),
+		Quantity:        e.Quantity,
+		Seller:          account(e.Seller),
+		Buyer:           account(e.WinnerAccount),
+		FromAddress:     account(e.FromAccount),
+		ToAddress:       account(e.ToAccount),
+		AuctionType:     e.AuctionType,
+		IsPrivate:       e.IsPrivate,
+	}
+	if a := e.Asset; a != nil {
+		r.TokenID, r.AssetName = a.TokenID, a.Name
+		// The contract address of sales is the exchange's; the asset's is the token's.
+		if a.AssetContract != nil {
+			r.ContractAddress = a.AssetContract.Address.String()
+		}
+		if a.Collection != nil && r.CollectionSlug == "" {
+			r.CollectionSlug = a.Collection.Slug
+		}
+	}
+	if t := e.Transaction; t != nil {
+		r.TransactionHash, r.BlockNumber = t.TransactionHash, int64Ptr(t.BlockNumber)
+	}
+
+	amount := e.TotalPrice
+	if amount.Int == nil {
+		amount = e.BidAmount
+	}
+	if amount.Int == nil {
+		amount = e.StartingPrice
+	}
+	var ethPrice, usdPrice *big.Rat
+	if t := e.PaymentToken; t != nil {
+		r.PaymentTokenSymbol, r.PaymentTokenAddress = t.Symbol, t.Address.String()
+		ethPrice, usdPrice = t.EthPrice.Rat(), t.UsdPrice.Rat()
+	}
+	r.Price, r.PriceDecimals, r.PriceEth = price(amount, ethPrice)
+	_, _, r.PriceUsd = price(amount, usdPrice)
+	return r
+}
+
+// EventWriter writes events as EventRecord rows.
+type EventWriter struct {
+	rw recordWriter
+}
+
+func NewEventWriter(w io.Writer, f Format) (*EventWriter, error) {
+	rw, err := newRecordWriter(w, f, new(EventRecord))
+	if err != nil {
+		return nil, err
+	}
+	return &EventWriter{rw: rw}, nil
+}
+
+func (w *EventWriter) Write(e *opensea.Event) error {
+	r := NewEventRecord(e)
+	return w.rw.write(&r)
+}
+
+// Close flushes the rows written, and the footer of a Parquet file; it does not close the underlying writer.
+func (w *EventWriter) Close() error {
+	return w.rw.close()
+}
+
+// WriteEvents writes events to w in format f.
+func WriteEvents(w io.Writer, f Format, events []*opensea.Event) error {
+	ew, err := NewEventWriter(w, f)
+	if err != nil {
+		return err
+	}
+	for i, e := range events {
+		if err := ew.Write(e); err != nil {
+			return fmt.Errorf("export: event %d: %w", i, err)
+		}
+	}
+	return ew.Close()
+}
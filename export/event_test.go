@@ -0,0 +1,83 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+
+	"github.com/jumpblock/go-opensea"
+	"github.com/stretchr/testify/assert"
+	"github.com/xitongsys/parquet-go-source/buffer"
+	"github.com/xitongsys/parquet-go/reader"
+)
+
+func fixtureEvents(t *testing.T) []*opensea.Event {
+	b, err := ioutil.ReadFile("../test-files/opensea-events.json")
+	assert.Nil(t, err)
+	var resp opensea.AssetEventsResponse
+	assert.Nil(t, json.Unmarshal(b, &resp))
+	return resp.AssetEvents
+}
+
+func TestEventsCSV(t *testing.T) {
+	events := fixtureEvents(t)
+	var buf bytes.Buffer
+	assert.Nil(t, WriteEvents(&buf, FormatCSV, events))
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	assert.Nil(t, err)
+	assert.Len(t, rows, len(events)+1)
+	assert.Equal(t, []string{
+		"id", "event_type", "timestamp", "created_date", "collection_slug", "contract_address", "token_id",
+		"asset_name", "quantity", "seller", "buyer", "from_address", "to_address", "price", "price_decimals",
+		"price_eth", "price_usd", "payment_token_symbol", "payment_token_address", "transaction_hash",
+		"block_number", "auction_type", "is_private",
+	}, rows[0])
+
+	var sale map[string]string
+	for _, row := range rows[1:] {
+		if row[0] == "651665248" {
+			sale = map[string]string{}
+			for i, c := range rows[0] {
+				sale[c] = row[i]
+			}
+		}
+	}
+	assert.Equal(t, "successful", sale["event_type"])
+	assert.Equal(t, "2021-08-26T22:48:39.936Z", sale["timestamp"])
+	assert.Equal(t, "forgotten-runes-mafriends", sale["collection_slug"])
+	assert.Equal(t, "0x495f947276749ce646f68ac8c248420045cb7b5e", sale["contract_address"])
+	assert.Equal(t, "200000000000000000", sale["price"])
+	assert.Equal(t, "18", sale["price_decimals"])
+	assert.Equal(t, "0.2", sale["price_eth"])
+	assert.Equal(t, "WETH", sale["payment_token_symbol"])
+	assert.Equal(t, "0x45aef14112f92bdc3fa08ca0d85c8a96af47c490", sale["buyer"])
+	assert.Equal(t, "0xf2a9c30f322f9c6588e394c3efc2ccdb0220dff07a7b8742f1f8e6a9f2e75baf", sale["transaction_hash"])
+	assert.NotEmpty(t, sale["block_number"])
+}
+
+func TestEventsParquet(t *testing.T) {
+	events := fixtureEvents(t)
+	var buf bytes.Buffer
+	assert.Nil(t, WriteEvents(&buf, FormatParquet, events))
+
+	f, err := buffer.NewBufferFile(buf.Bytes())
+	assert.Nil(t, err)
+	pr, err := reader.NewParquetReader(f, new(EventRecord), 1)
+	assert.Nil(t, err)
+	defer pr.ReadStop()
+	assert.Equal(t, int64(len(events)), pr.GetNumRows())
+
+	rows := make([]EventRecord, len(events))
+	assert.Nil(t, pr.Read(&rows))
+	for i, e := range events {
+		assert.Equal(t, NewEventRecord(e), rows[i])
+	}
+}
+
+func TestUnknownFormat(t *testing.T) {
+	_, err := NewEventWriter(ioutil.Discard, Format("xlsx"))
+	assert.EqualError(t, err, `export: unknown format "xlsx"`)
+}
@@ -0,0 +1,200 @@
+// Package export writes events and orders as flat CSV or Parquet files with a stable column schema,
+// one row at a time, so that exports of any size run in constant memory. Feed a writer from a slice,
+// or straight from a pager such as Opensea.WalkEvents:
+//
+//	w, _ := export.NewEventWriter(f, export.FormatParquet)
+//	err := o.WalkEvents(ctx, params, w.Write)
+//	err = w.Close()
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math/big"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jumpblock/go-opensea"
+	"github.com/xitongsys/parquet-go/parquet"
+	"github.com/xitongsys/parquet-go/writer"
+)
+
+type Format string
+
+const (
+	FormatCSV     Format = "csv"
+	FormatParquet Format = "parquet"
+)
+
+// rowGroupSize bounds the rows a Parquet writer holds before flushing them to the file.
+const rowGroupSize = 16 << 20
+
+// recordWriter writes records of one struct type, whose parquet tags give the columns.
+type recordWriter interface {
+	write(rec interface{}) error
+	close() error
+}
+
+func newRecordWriter(w io.Writer, f Format, proto interface{}) (recordWriter, error) {
+	switch f {
+	case FormatCSV:
+		cw := &csvWriter{w: csv.NewWriter(w), columns: columnsOf(reflect.TypeOf(proto))}
+		header := make([]string, len(cw.columns))
+		for i, c := range cw.columns {
+			header[i] = c.name
+		}
+		if err := cw.w.Write(header); err != nil {
+			return nil, err
+		}
+		return cw, nil
+	case FormatParquet:
+		pw, err := writer.NewParquetWriterFromWriter(w, proto, 4)
+		if err != nil {
+			return nil, err
+		}
+		pw.RowGroupSize = rowGroupSize
+		pw.CompressionType = parquet.CompressionCodec_SNAPPY
+		return parquetWriter{pw}, nil
+	}
+	return nil, fmt.Errorf("export: unknown format %q", f)
+}
+
+type column struct {
+	name      string
+	field     int
+	timestamp bool // milliseconds, written as RFC 3339 in CSV
+}
+
+func columnsOf(t reflect.Type) []column {
+	if t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	var cols []column
+	for i := 0; i < t.NumField(); i++ {
+		tag := t.Field(i).Tag.Get("parquet")
+		c := column{field: i}
+		for _, part := range strings.Split(tag, ",") {
+			kv := strings.SplitN(strings.TrimSpace(part), "=", 2)
+			if len(kv) != 2 {
+				continue
+			}
+			switch kv[0] {
+			case "name":
+				c.name = kv[1]
+			case "convertedtype":
+				c.timestamp = kv[1] == "TIMESTAMP_MILLIS"
+			}
+		}
+		if c.name != "" {
+			cols = append(cols, c)
+		}
+	}
+	return cols
+}
+
+type csvWriter struct {
+	w       *csv.Writer
+	columns []column
+	record  []string
+}
+
+func (cw *csvWriter) write(rec interface{}) error {
+	v := reflect.Indirect(reflect.ValueOf(rec))
+	if cw.record == nil {
+		cw.record = make([]string, len(cw.columns))
+	}
+	for i, c := range cw.columns {
+		cw.record[i] = formatValue(v.Field(c.field), c.timestamp)
+	}
+	return cw.w.Write(cw.record)
+}
+
+func (cw *csvWriter) close() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+// formatValue writes nulls as empty cells.
+func formatValue(v reflect.Value, timestamp bool) string {
+	if v.Kind() == reflect.Ptr {
+		if v.IsNil() {
+			return ""
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return strconv.FormatBool(v.Bool())
+	case reflect.Int32, reflect.Int64:
+		if timestamp {
+			return time.Unix(0, v.Int()*int64(time.Millisecond)).UTC().Format(time.RFC3339Nano)
+		}
+		return strconv.FormatInt(v.Int(), 10)
+	case reflect.Float64:
+		return strconv.FormatFloat(v.Float(), 'f', -1, 64)
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+type parquetWriter struct {
+	pw *writer.ParquetWriter
+}
+
+func (p parquetWriter) write(rec interface{}) error {
+	return p.pw.Write(rec)
+}
+
+func (p parquetWriter) close() error {
+	return p.pw.WriteStop()
+}
+
+// millis returns a TIMESTAMP_MILLIS column value, null for the zero time.
+func millis(t opensea.TimeNano) *int64 {
+	if t.IsZero() {
+		return nil
+	}
+	ms := t.Time().UnixNano() / int64(time.Millisecond)
+	return &ms
+}
+
+// price fills the price columns shared by events and orders. ethPrice is the value of one token in
+// ETH, nil when unknown.
+func price(a opensea.Amount, ethPrice *big.Rat) (base *string, decimals int32, eth *float64) {
+	if a.Int == nil {
+		return nil, 0, nil
+	}
+	base, decimals = stringPtr(a.String()), int32(a.Decimals)
+	if ethPrice != nil {
+		v := new(big.Rat).SetFrac(a.Big(), new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(a.Decimals)), nil))
+		f, _ := v.Mul(v, ethPrice).Float64()
+		eth = &f
+	}
+	return
+}
+
+func account(a *opensea.Account) string {
+	if a == nil {
+		return ""
+	}
+	return a.Address.String()
+}
+
+func int64Ptr(s string) *int64 {
+	n, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return nil
+	}
+	return &n
+}
+
+func stringPtr(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
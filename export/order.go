@@ -0,0 +1,221 @@
+package export
+
+import (
+	"fmt"
+	"io"
+	"math/big"
+
+	"github.com/jumpblock/go-opensea"
+)
+
+// OrderRecord is the row of an order, from the v1 order book or Seaport. Side is "ask" for listings
+// and "bid" for offers. PriceEth is only set for orders paid in ETH or WETH, as orders carry no
+// token prices.
+type OrderRecord struct {
+	Protocol            string   `parquet:"name=protocol, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	OrderHash           string   `parquet:"name=order_hash, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Side                string   `parquet:"name=side, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	OrderType           string   `parquet:"name=order_type, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	CreatedDate         *int64   `parquet:"name=created_date, type=INT64, convertedtype=TIMESTAMP_MILLIS, repetitiontype=OPTIONAL"`
+	ListingTime         *int64   `parquet:"name=listing_time, type=INT64, convertedtype=TIMESTAMP_MILLIS, repetitiontype=OPTIONAL"`
+	ExpirationTime      *int64   `parquet:"name=expiration_time, type=INT64, convertedtype=TIMESTAMP_MILLIS, repetitiontype=OPTIONAL"`
+	ClosingDate         *int64   `parquet:"name=closing_date, type=INT64, convertedtype=TIMESTAMP_MILLIS, repetitiontype=OPTIONAL"`
+	Maker               string   `parquet:"name=maker, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Taker               string   `parquet:"name=taker, type=BYTE_ARRAY, convertedtype=UTF8"`
+	CollectionSlug      string   `parquet:"name=collection_slug, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	ContractAddress     string   `parquet:"name=contract_address, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	TokenID             string   `parquet:"name=token_id, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Quantity            string   `parquet:"name=quantity, type=BYTE_ARRAY, convertedtype=UTF8"`
+	Price               *string  `parquet:"name=price, type=BYTE_ARRAY, convertedtype=UTF8, repetitiontype=OPTIONAL"`
+	PriceDecimals       int32    `parquet:"name=price_decimals, type=INT32"`
+	PriceEth            *float64 `parquet:"name=price_eth, type=DOUBLE, repetitiontype=OPTIONAL"`
+	PaymentTokenSymbol  string   `parquet:"name=payment_token_symbol, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	PaymentTokenAddress string   `parquet:"name=payment_token_address, type=BYTE_ARRAY, convertedtype=UTF8, encoding=PLAIN_DICTIONARY"`
+	Cancelled           bool     `parquet:"name=cancelled, type=BOOLEAN"`
+	Finalized           bool     `parquet:"name=finalized, type=BOOLEAN"`
+	MarkedInvalid       bool     `parquet:"name=marked_invalid, type=BOOLEAN"`
+}
+
+// NewOrderRecord flattens a v1 order.
+func NewOrderRecord(chain opensea.Chain, o *opensea.Order) OrderRecord {
+	r := OrderRecord{
+		Protocol:            "v1",
+		OrderHash:           o.OrderHash,
+		Side:                "ask",
+		OrderType:           "basic",
+		ListingTime:         millis(o.ListingTime),
+		ExpirationTime:      millis(o.ExpirationTime),
+		Maker:               o.Maker.Address.String(),
+		Taker:               o.Taker.Address.String(),
+		TokenID:             o.Asset.TokenID,
+		Quantity:            o.Quantity,
+		PaymentTokenAddress: o.PaymentToken.String(),
+		Cancelled:           o.Cancelled,
+		Finalized:           o.Finalized,
+		MarkedInvalid:       o.MarkedInvalid,
+	}
+	if o.Side == opensea.Buy {
+		r.Side = "bid"
+	}
+	if o.SaleKind == opensea.DutchAuctions {
+		r.OrderType = "dutch"
+	}
+	if o.CreatedDate != nil {
+		r.CreatedDate = millis(*o.CreatedDate)
+	}
+	if o.ClosingDate != nil {
+		r.ClosingDate = millis(*o.ClosingDate)
+	}
+	if o.Asset.AssetContract != nil {
+		r.ContractAddress = o.Asset.AssetContract.Address.String()
+	}
+	if o.Asset.Collection != nil {
+		r.CollectionSlug = o.Asset.Collection.Slug
+	}
+
+	amount := o.CurrentPrice
+	var ethPrice *big.Rat
+	if t, err := opensea.DefaultTokenRegistry.Resolve(chain, o.PaymentToken.String()); err == nil {
+		r.PaymentTokenSymbol = t.Symbol
+		amount = amount.WithDecimals(t.Decimals)
+		ethPrice = etherValue(t)
+	}
+	r.Price, r.PriceDecimals, r.PriceEth = price(amount, ethPrice)
+	return r
+}
+
+// NewOrderRecordV2 flattens a Seaport order; the token is the first asset of the bundle it trades.
+func NewOrderRecordV2(chain opensea.Chain, o *opensea.OrderV2) OrderRecord {
+	r := OrderRecord{
+		Protocol:            "seaport",
+		OrderHash:           o.OrderHash,
+		Side:                o.Side,
+		OrderType:           o.OrderType,
+		ListingTime:         millis(o.ListingTime),
+		ExpirationTime:      millis(o.ExpirationTime),
+		Maker:               account(o.Maker),
+		Taker:               account(o.Taker),
+		PaymentTokenAddress: o.PaymentTokenAddress().String(),
+		Cancelled:           o.Cancelled,
+		Finalized:           o.Finalized,
+		MarkedInvalid:       o.MarkedInvalid,
+	}
+	if o.CreatedDate != nil {
+		r.CreatedDate = millis(*o.CreatedDate)
+	}
+	if o.ClosingDate != nil {
+		r.ClosingDate = millis(*o.ClosingDate)
+	}
+	bundle := o.MakerAssetBundle
+	if o.Side == "bid" {
+		bundle = o.TakerAssetBundle
+	}
+	if bundle != nil && len(bundle.Assets) > 0 {
+		a := bundle.Assets[0]
+		r.TokenID = a.TokenID
+		if a.AssetContract != nil {
+			r.ContractAddress = a.AssetContract.Address.String()
+		}
+		if a.Collection != nil {
+			r.CollectionSlug = a.Collection.Slug
+		}
+	}
+	if p := o.ProtocolData; p != nil && p.Parameters != nil {
+		items := p.Parameters.Offer
+		if o.Side == "bid" {
+			items = nil
+			for _, c := range p.Parameters.Consideration {
+				items = append(items, c.OfferItem)
+			}
+		}
+		for _, v := range items {
+			if v.ItemType == opensea.ItemERC721 || v.ItemType == opensea.ItemERC1155 {
+				r.Quantity = string(v.StartAmount)
+				break
+			}
+		}
+	}
+
+	amount, t, err := opensea.DefaultTokenRegistry.OrderPrice(chain, o)
+	var ethPrice *big.Rat
+	if err == nil {
+		r.PaymentTokenSymbol = t.Symbol
+		ethPrice = etherValue(t)
+	}
+	r.Price, r.PriceDecimals, r.PriceEth = price(amount, ethPrice)
+	return r
+}
+
+// etherValue is 1 for ETH and WETH, whose value in ETH is known without a price feed.
+func etherValue(t opensea.TokenInfo) *big.Rat {
+	if t.Symbol == "ETH" || t.Symbol == "WETH" {
+		return big.NewRat(1, 1)
+	}
+	return nil
+}
+
+// OrderWriter writes v1 and Seaport orders as OrderRecord rows. Chain resolves the payment tokens,
+// Ethereum if empty.
+type OrderWriter struct {
+	Chain opensea.Chain
+
+	rw recordWriter
+}
+
+func NewOrderWriter(w io.Writer, f Format) (*OrderWriter, error) {
+	rw, err := newRecordWriter(w, f, new(OrderRecord))
+	if err != nil {
+		return nil, err
+	}
+	return &OrderWriter{Chain: opensea.ChainEthereum, rw: rw}, nil
+}
+
+func (w *OrderWriter) Write(o *opensea.Order) error {
+	r := NewOrderRecord(w.chain(), o)
+	return w.rw.write(&r)
+}
+
+func (w *OrderWriter) WriteV2(o *opensea.OrderV2) error {
+	r := NewOrderRecordV2(w.chain(), o)
+	return w.rw.write(&r)
+}
+
+// Close flushes the rows written, and the footer of a Parquet file; it does not close the underlying writer.
+func (w *OrderWriter) Close() error {
+	return w.rw.close()
+}
+
+func (w *OrderWriter) chain() opensea.Chain {
+	if w.Chain == "" {
+		return opensea.ChainEthereum
+	}
+	return w.Chain
+}
+
+// WriteOrders writes v1 orders to w in format f.
+func WriteOrders(w io.Writer, f Format, orders []*opensea.Order) error {
+	ow, err := NewOrderWriter(w, f)
+	if err != nil {
+		return err
+	}
+	for i, o := range orders {
+		if err := ow.Write(o); err != nil {
+			return fmt.Errorf("export: order %d: %w", i, err)
+		}
+	}
+	return ow.Close()
+}
+
+// WriteOrdersV2 writes Seaport orders to w in format f.
+func WriteOrdersV2(w io.Writer, f Format, orders []*opensea.OrderV2) error {
+	ow, err := NewOrderWriter(w, f)
+	if err != nil {
+		return err
+	}
+	for i, o := range orders {
+		if err := ow.WriteV2(o); err != nil {
+			return fmt.Errorf("export: order %d: %w", i, err)
+		}
+	}
+	return ow.Close()
+}
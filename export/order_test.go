@@ -0,0 +1,74 @@
+package export
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+
+	"github.com/jumpblock/go-opensea"
+	"github.com/stretchr/testify/assert"
+	"github.com/xitongsys/parquet-go-source/buffer"
+	"github.com/xitongsys/parquet-go/reader"
+)
+
+func fixtureOrderV2(t *testing.T) *opensea.OrderV2 {
+	b, err := ioutil.ReadFile("../test-files/listings-v2.json")
+	assert.Nil(t, err)
+	o := new(opensea.OrderV2)
+	assert.Nil(t, json.Unmarshal(b, o))
+	return o
+}
+
+func TestOrderRecordV2(t *testing.T) {
+	r := NewOrderRecordV2(opensea.ChainEthereum, fixtureOrderV2(t))
+	assert.Equal(t, "seaport", r.Protocol)
+	assert.Equal(t, "ask", r.Side)
+	assert.Equal(t, "0xae0b379f8bf426fd2d50c47b7e7f7879cbf56f03c8e65f724c4feee699a4ccf0", r.OrderHash)
+	assert.Equal(t, "0x8d0cf15d459b98fcc84a56d86737f44ff2204751", r.Maker)
+	assert.Equal(t, "drunken-pandas-official", r.CollectionSlug)
+	assert.Equal(t, "0x9bfa45382268e4bacbd1175395728153dc5248f2", r.ContractAddress)
+	assert.Equal(t, "1998", r.TokenID)
+	assert.Equal(t, "1", r.Quantity)
+	assert.Equal(t, int64(1655337245000), *r.ListingTime)
+	assert.Equal(t, "1000000000000000000", *r.Price)
+	assert.Equal(t, int32(18), r.PriceDecimals)
+	assert.Equal(t, "ETH", r.PaymentTokenSymbol)
+	assert.Equal(t, 1.0, *r.PriceEth)
+
+	r = NewOrderRecordV2(opensea.ChainPolygon, fixtureOrderV2(t))
+	assert.Equal(t, "MATIC", r.PaymentTokenSymbol)
+	assert.Nil(t, r.PriceEth)
+}
+
+func TestOrdersV2CSV(t *testing.T) {
+	var buf bytes.Buffer
+	assert.Nil(t, WriteOrdersV2(&buf, FormatCSV, []*opensea.OrderV2{fixtureOrderV2(t)}))
+
+	rows, err := csv.NewReader(&buf).ReadAll()
+	assert.Nil(t, err)
+	assert.Len(t, rows, 2)
+	assert.Equal(t, "protocol", rows[0][0])
+	assert.Equal(t, "listing_time", rows[0][5])
+	assert.Equal(t, "2022-06-15T23:54:05Z", rows[1][5])
+	assert.Equal(t, "2022-07-15T23:54:05Z", rows[1][7])
+	assert.Equal(t, "", rows[1][9], "taker is empty")
+}
+
+func TestOrdersV2Parquet(t *testing.T) {
+	orders := []*opensea.OrderV2{fixtureOrderV2(t), fixtureOrderV2(t)}
+	var buf bytes.Buffer
+	assert.Nil(t, WriteOrdersV2(&buf, FormatParquet, orders))
+
+	f, err := buffer.NewBufferFile(buf.Bytes())
+	assert.Nil(t, err)
+	pr, err := reader.NewParquetReader(f, new(OrderRecord), 1)
+	assert.Nil(t, err)
+	defer pr.ReadStop()
+
+	rows := make([]OrderRecord, pr.GetNumRows())
+	assert.Len(t, rows, 2)
+	assert.Nil(t, pr.Read(&rows))
+	assert.Equal(t, NewOrderRecordV2(opensea.ChainEthereum, orders[0]), rows[1])
+}
@@ -0,0 +1,254 @@
+package opensea
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+type FloorSource string
+
+const (
+	FloorSourceNone    FloorSource = ""
+	FloorSourceListing FloorSource = "listing"
+	FloorSourceStats   FloorSource = "stats"
+)
+
+// Floor is the floor price of a collection in ETH. Order is the listing that sets it,
+// nil when the price comes from the collection stats.
+type Floor struct {
+	Collection string
+	Price      Amount
+	Source     FloorSource
+	Order      *OrderV2
+	UpdatedAt  time.Time
+}
+
+type FloorChange struct {
+	Previous Floor
+	Current  Floor
+}
+
+// FloorTracker keeps the live floor of each collection from the cheapest valid listing it has seen,
+// falling back to the stats floor when there is none, and calls OnChange whenever a floor moves.
+type FloorTracker struct {
+	OnChange func(FloorChange)
+
+	mu          sync.Mutex
+	now         func() time.Time
+	collections map[string]*floorBook
+}
+
+type floorBook struct {
+	listings   map[string]*OrderV2 // by order hash
+	statsFloor *Amount
+	floor      Floor
+}
+
+func NewFloorTracker(onChange func(FloorChange)) *FloorTracker {
+	return &FloorTracker{
+		OnChange:    onChange,
+		now:         time.Now,
+		collections: map[string]*floorBook{},
+	}
+}
+
+func (t *FloorTracker) Floor(collection string) (Floor, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	b, ok := t.collections[collection]
+	if !ok || b.floor.Source == FloorSourceNone {
+		return Floor{}, false
+	}
+	return b.floor, true
+}
+
+// UpdateListings adds listings of a collection, replacing those with the same order hash.
+// Listings that can not be bought at their price with ETH are dropped.
+func (t *FloorTracker) UpdateListings(collection string, orders []*OrderV2) {
+	t.mu.Lock()
+	b := t.book(collection)
+	now := t.now()
+	for _, v := range orders {
+		if v == nil || v.OrderHash == "" {
+			continue
+		}
+		if IsValidEthListing(v, now) {
+			b.listings[v.OrderHash] = v
+		} else {
+			delete(b.listings, v.OrderHash)
+		}
+	}
+	change, changed := t.recompute(collection, b)
+	t.mu.Unlock()
+	t.notify(change, changed)
+}
+
+// UpdateStats records the floor OpenSea reports for a collection, used when no listing is known.
+func (t *FloorTracker) UpdateStats(collection string, stat Stat) {
+	t.mu.Lock()
+	b := t.book(collection)
+	b.statsFloor = nil
+	if stat.FloorPrice.Sign() > 0 {
+		b.statsFloor = &stat.FloorPrice.Amount
+	}
+	change, changed := t.recompute(collection, b)
+	t.mu.Unlock()
+	t.notify(change, changed)
+}
+
+// HandleEvent drops the listings of an asset that was sold or transferred, and the listing
+// a cancel event names by its order hash. Cancels without an order hash are ignored.
+func (t *FloorTracker) HandleEvent(e *Event) {
+	if e == nil || e.Asset == nil {
+		return
+	}
+	kind := e.Kind()
+	switch kind {
+	case EventKindSale, EventKindTransfer:
+	case EventKindCancel:
+		if e.OrderHash == "" {
+			return
+		}
+	default:
+		return
+	}
+	collection := e.CollectionSlug
+	if collection == "" && e.Asset.Collection != nil {
+		collection = e.Asset.Collection.Slug
+	}
+	contract := e.ContractAddress.String()
+	if e.Asset.AssetContract != nil {
+		contract = e.Asset.AssetContract.Address.String()
+	}
+
+	t.mu.Lock()
+	b, ok := t.collections[collection]
+	if !ok {
+		t.mu.Unlock()
+		return
+	}
+	for hash, v := range b.listings {
+		if kind == EventKindCancel {
+			if strings.EqualFold(hash, e.OrderHash) {
+				delete(b.listings, hash)
+			}
+			continue
+		}
+		c, id := listingAsset(v)
+		if strings.EqualFold(c, contract) && id == e.Asset.TokenID {
+			delete(b.listings, hash)
+		}
+	}
+	change, changed := t.recompute(collection, b)
+	t.mu.Unlock()
+	t.notify(change, changed)
+}
+
+// RefreshStats fetches the collection stats and records its floor.
+func (t *FloorTracker) RefreshStats(ctx context.Context, o Opensea, slug string) error {
+	stat, err := o.GetCollectionStatsWithContext(ctx, slug)
+	if err != nil {
+		return err
+	}
+	t.UpdateStats(slug, *stat)
+	return nil
+}
+
+func (t *FloorTracker) book(collection string) *floorBook {
+	b, ok := t.collections[collection]
+	if !ok {
+		b = &floorBook{listings: map[string]*OrderV2{}}
+		t.collections[collection] = b
+	}
+	return b
+}
+
+func (t *FloorTracker) recompute(collection string, b *floorBook) (FloorChange, bool) {
+	now := t.now()
+	next := Floor{Collection: collection, UpdatedAt: now}
+	for hash, v := range b.listings {
+		if !IsValidEthListing(v, now) {
+			delete(b.listings, hash)
+			continue
+		}
+		price := v.CurrentPrice
+		if next.Order == nil || price.Cmp(next.Price) < 0 || (price.Cmp(next.Price) == 0 && v.OrderHash < next.Order.OrderHash) {
+			next.Price = price
+			next.Order = v
+			next.Source = FloorSourceListing
+		}
+	}
+	if next.Order == nil && b.statsFloor != nil {
+		next.Price = *b.statsFloor
+		next.Source = FloorSourceStats
+	}
+
+	prev := b.floor
+	if prev.Source == next.Source && prev.Price.Cmp(next.Price) == 0 && sameOrder(prev.Order, next.Order) {
+		return FloorChange{}, false
+	}
+	b.floor = next
+	return FloorChange{Previous: prev, Current: next}, true
+}
+
+func (t *FloorTracker) notify(change FloorChange, changed bool) {
+	if changed && t.OnChange != nil {
+		t.OnChange(change)
+	}
+}
+
+// IsValidEthListing reports whether o is a public, live sell order paid entirely in ETH.
+func IsValidEthListing(o *OrderV2, now time.Time) bool {
+	if o == nil || o.Side != "ask" {
+		return false
+	}
+	if o.Cancelled || o.Finalized || o.MarkedInvalid {
+		return false
+	}
+	if o.Taker != nil && o.Taker.Address != "" && !o.Taker.Address.IsNullAddress() {
+		return false
+	}
+	if !o.ExpirationTime.IsZero() && !o.ExpirationTime.Time().After(now) {
+		return false
+	}
+	if o.ListingTime.Time().After(now) {
+		return false
+	}
+	if o.ProtocolData == nil || o.ProtocolData.Parameters == nil {
+		return false
+	}
+	// an ERC20 is not ETH, and an NFT in the consideration is owed to the private buyer
+	for _, v := range o.ProtocolData.Parameters.Consideration {
+		if v.ItemType != ItemNative {
+			return false
+		}
+	}
+	return true
+}
+
+func listingAsset(o *OrderV2) (contract, tokenID string) {
+	if o.ProtocolData != nil && o.ProtocolData.Parameters != nil {
+		for _, v := range o.ProtocolData.Parameters.Offer {
+			if v.ItemType == ItemERC721 || v.ItemType == ItemERC1155 {
+				return v.Token.String(), v.IdentifierOrCriteria
+			}
+		}
+	}
+	if o.MakerAssetBundle != nil && len(o.MakerAssetBundle.Assets) > 0 {
+		a := o.MakerAssetBundle.Assets[0]
+		if a.AssetContract != nil {
+			contract = a.AssetContract.Address.String()
+		}
+		return contract, a.TokenID
+	}
+	return "", ""
+}
+
+func sameOrder(a, b *OrderV2) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.OrderHash == b.OrderHash
+}
@@ -0,0 +1,156 @@
+package opensea
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func loadListing(t *testing.T) *OrderV2 {
+	b, err := ioutil.ReadFile("test-files/listings-v2.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	ord := new(OrderV2)
+	if err := json.Unmarshal(b, ord); err != nil {
+		t.Fatal(err)
+	}
+	ord.MarkedInvalid = false
+	return ord
+}
+
+func listingAt(t *testing.T, hash, price string) *OrderV2 {
+	ord := loadListing(t)
+	ord.OrderHash = hash
+	ord.CurrentPrice = Ether(price)
+	return ord
+}
+
+func TestIsValidEthListing(t *testing.T) {
+	ord := loadListing(t)
+	now := ord.ListingTime.Time().Add(time.Minute)
+	assert.True(t, IsValidEthListing(ord, now))
+
+	assert.False(t, IsValidEthListing(ord, ord.ExpirationTime.Time()))
+	assert.False(t, IsValidEthListing(ord, ord.ListingTime.Time().Add(-time.Second)))
+
+	cancelled := *ord
+	cancelled.Cancelled = true
+	assert.False(t, IsValidEthListing(&cancelled, now))
+
+	private := *ord
+	private.Taker = &Account{Address: "0x8d0cf15d459b98fcc84a56d86737f44ff2204751"}
+	assert.False(t, IsValidEthListing(&private, now))
+
+	weth := loadListing(t)
+	weth.ProtocolData.Parameters.Consideration[0].ItemType = ItemERC20
+	assert.False(t, IsValidEthListing(weth, now))
+}
+
+func TestFloorTracker(t *testing.T) {
+	var changes []FloorChange
+	ft := NewFloorTracker(func(c FloorChange) {
+		changes = append(changes, c)
+	})
+	cheap := listingAt(t, "0x01", "0.9")
+	ft.now = func() time.Time { return cheap.ListingTime.Time().Add(time.Minute) }
+
+	ft.UpdateStats("pandas", Stat{FloorPrice: EtherAmount{Ether("2.6")}})
+	f, ok := ft.Floor("pandas")
+	assert.True(t, ok)
+	assert.Equal(t, FloorSourceStats, f.Source)
+	assert.Equal(t, "2600000000000000000", f.Price.String())
+
+	ft.UpdateListings("pandas", []*OrderV2{listingAt(t, "0x02", "1"), cheap})
+	f, _ = ft.Floor("pandas")
+	assert.Equal(t, FloorSourceListing, f.Source)
+	assert.Equal(t, "0x01", f.Order.OrderHash)
+	assert.Equal(t, 2, len(changes))
+	assert.Equal(t, FloorSourceStats, changes[1].Previous.Source)
+
+	// the same listings again do not notify
+	ft.UpdateListings("pandas", []*OrderV2{cheap})
+	assert.Equal(t, 2, len(changes))
+
+	// the cheap token is sold, the other listing is for the same token in the fixture
+	ft.HandleEvent(&Event{
+		EventType:      EventTypeSuccessful,
+		CollectionSlug: "pandas",
+		Asset: &Asset{
+			TokenID:       "1998",
+			AssetContract: &AssetContract{Address: "0x9bfa45382268e4bacbd1175395728153dc5248f2"},
+		},
+	})
+	f, _ = ft.Floor("pandas")
+	assert.Equal(t, FloorSourceStats, f.Source)
+	assert.Equal(t, "2.6", f.Price.Units())
+	assert.Equal(t, 3, len(changes))
+	assert.Equal(t, "0x01", changes[2].Previous.Order.OrderHash)
+}
+
+func TestFloorTrackerNewerEventTypes(t *testing.T) {
+	types := []EventType{EventTypeSale, EventTypeTransfer, EventTypeCancel, EventTypeItemSold, EventTypeItemTransferred, EventTypeItemCancelled}
+	for _, typ := range types {
+		ft := NewFloorTracker(nil)
+		cheap := listingAt(t, "0x01", "0.9")
+		ft.now = func() time.Time { return cheap.ListingTime.Time().Add(time.Minute) }
+		ft.UpdateListings("pandas", []*OrderV2{cheap})
+
+		asset := &Asset{TokenID: "1998", AssetContract: &AssetContract{Address: "0x9bfa45382268e4bacbd1175395728153dc5248f2"}}
+		ft.HandleEvent(&Event{EventType: EventTypeItemListed, CollectionSlug: "pandas", Asset: asset})
+		_, ok := ft.Floor("pandas")
+		assert.True(t, ok, typ)
+
+		ft.HandleEvent(&Event{EventType: typ, CollectionSlug: "pandas", Asset: asset, OrderHash: "0x01"})
+		_, ok = ft.Floor("pandas")
+		assert.False(t, ok, typ)
+	}
+}
+
+func TestFloorTrackerCancelByOrderHash(t *testing.T) {
+	ft := NewFloorTracker(nil)
+	cheap := listingAt(t, "0x01", "0.9")
+	ft.now = func() time.Time { return cheap.ListingTime.Time().Add(time.Minute) }
+	// both listings are for the same token in the fixture
+	ft.UpdateListings("pandas", []*OrderV2{cheap, listingAt(t, "0x02", "1")})
+
+	asset := &Asset{TokenID: "1998", AssetContract: &AssetContract{Address: "0x9bfa45382268e4bacbd1175395728153dc5248f2"}}
+	ft.HandleEvent(&Event{EventType: EventTypeItemCancelled, CollectionSlug: "pandas", Asset: asset})
+	f, _ := ft.Floor("pandas")
+	assert.Equal(t, "0x01", f.Order.OrderHash)
+
+	ft.HandleEvent(&Event{EventType: EventTypeItemCancelled, CollectionSlug: "pandas", Asset: asset, OrderHash: "0x01"})
+	f, ok := ft.Floor("pandas")
+	assert.True(t, ok)
+	assert.Equal(t, "0x02", f.Order.OrderHash)
+
+	ft.HandleEvent(&Event{EventType: EventTypeItemCancelled, CollectionSlug: "pandas", Asset: asset, OrderHash: "0x02"})
+	_, ok = ft.Floor("pandas")
+	assert.False(t, ok)
+}
+
+func TestGetCollectionStats(t *testing.T) {
+	b, err := ioutil.ReadFile("test-files/opensea-stats-doodles.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/api/v1/collection/doodles-official/stats", r.URL.Path)
+		w.Write(b)
+	}))
+	defer srv.Close()
+
+	client := Opensea{API: srv.URL, httpClient: srv.Client()}
+	ft := NewFloorTracker(nil)
+	err = ft.RefreshStats(context.Background(), client, "doodles-official")
+	assert.Nil(t, err)
+	f, ok := ft.Floor("doodles-official")
+	assert.True(t, ok)
+	assert.Equal(t, FloorSourceStats, f.Source)
+}
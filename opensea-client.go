@@ -10,6 +10,8 @@ import (
 	"net"
 	"net/http"
 	"time"
+
+	"golang.org/x/time/rate"
 )
 
 var (
@@ -20,8 +22,16 @@ var (
 type Opensea struct {
 	API        string
 	APIKey     string
+	Chain      Chain
 	httpClient *http.Client
 	proxy      string
+	flights    *flightGroup
+
+	// Cache, when set, keeps the responses of the GET paths matching CacheRules (DefaultCacheRules if nil).
+	Cache      Cache
+	CacheRules []CacheRule
+	// Limiter, when set, paces the requests sent to OpenSea; cached and coalesced calls do not count.
+	Limiter *rate.Limiter
 }
 
 type errorResponse struct {
@@ -37,7 +47,9 @@ func NewOpensea(apiKey string) (*Opensea, error) {
 	o := &Opensea{
 		API:        mainnetAPI,
 		APIKey:     apiKey,
+		Chain:      ChainEthereum,
 		httpClient: defaultHttpClient(),
+		flights:    newFlightGroup(),
 	}
 	return o, nil
 }
@@ -45,7 +57,9 @@ func NewOpenseaWithProxy(apiKey, proxy string) (*Opensea, error) {
 	o := &Opensea{
 		API:        mainnetAPI,
 		APIKey:     apiKey,
+		Chain:      ChainEthereum,
 		httpClient: defaultHttpClient(),
+		flights:    newFlightGroup(),
 		proxy:      proxy,
 	}
 	return o, nil
@@ -55,30 +69,35 @@ func NewOpenseaRinkeby(apiKey string) (*Opensea, error) {
 	o := &Opensea{
 		API:        rinkebyAPI,
 		APIKey:     apiKey,
+		Chain:      ChainRinkeby,
 		httpClient: defaultHttpClient(),
+		flights:    newFlightGroup(),
 	}
 	return o, nil
 }
 
-// TODO
-//func (o Opensea) GetAssets(params GetAssetsParams) (*AssetResponse, error) {
-//	ctx := context.TODO()
-//	return o.GetAssetsWithContext(ctx, params)
-//}
+func (o Opensea) GetAssets(params GetAssetsParams) (*AssetResponse, error) {
+	ctx := context.TODO()
+	return o.GetAssetsWithContext(ctx, params)
+}
 
-// TODO
-//func (o Opensea) GetAssetsWithContext(ctx context.Context, params GetAssetsParams) (*AssetResponse, error) {
-//	path := fmt.Sprintf("/api/v1/assets")
-//	b, err := o.GetPath(ctx, path)
-//	if err != nil {
-//		return nil, err
-//	}
-//	ret := new(AssetResponse)
-//	return ret, json.Unmarshal(b, ret)
-//}
+// GetAssetsWithContext returns one page of assets, AssetResponse.Next is the cursor of the following page.
+func (o Opensea) GetAssetsWithContext(ctx context.Context, params GetAssetsParams) (*AssetResponse, error) {
+	path := "/api/v1/assets?" + params.Encode()
+	b, err := o.GetPath(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	ret := new(AssetResponse)
+	return ret, json.Unmarshal(b, ret)
+}
 
 func (o Opensea) GetCollections(offset, limit int) ([]CollectionSingle, error) {
 	ctx := context.TODO()
+	return o.GetCollectionsWithContext(ctx, offset, limit)
+}
+
+func (o Opensea) GetCollectionsWithContext(ctx context.Context, offset, limit int) ([]CollectionSingle, error) {
 	path := fmt.Sprintf("/api/v1/collections?offset=%d&limit=%d", offset, limit)
 	b, err := o.GetPath(ctx, path)
 	if err != nil {
@@ -93,6 +112,10 @@ func (o Opensea) GetCollections(offset, limit int) ([]CollectionSingle, error) {
 }
 func (o Opensea) GetSingleCollection(slug string) (CollectionSingle, error) {
 	ctx := context.TODO()
+	return o.GetSingleCollectionWithContext(ctx, slug)
+}
+
+func (o Opensea) GetSingleCollectionWithContext(ctx context.Context, slug string) (CollectionSingle, error) {
 	path := fmt.Sprintf("/api/v1/collection/%s", slug)
 	b, err := o.GetPath(ctx, path)
 	if err != nil {
@@ -106,6 +129,25 @@ func (o Opensea) GetSingleCollection(slug string) (CollectionSingle, error) {
 	return resp.Collection, nil
 }
 
+func (o Opensea) GetCollectionStats(slug string) (*Stat, error) {
+	ctx := context.TODO()
+	return o.GetCollectionStatsWithContext(ctx, slug)
+}
+
+func (o Opensea) GetCollectionStatsWithContext(ctx context.Context, slug string) (*Stat, error) {
+	path := fmt.Sprintf("/api/v1/collection/%s/stats", slug)
+	b, err := o.GetPath(ctx, path)
+	if err != nil {
+		return nil, err
+	}
+	resp := new(StatResponse)
+	err = json.Unmarshal(b, resp)
+	if err != nil {
+		return nil, err
+	}
+	return &resp.Stats, nil
+}
+
 func (o Opensea) GetSingleAsset(assetContractAddress string, tokenID *big.Int) (*Asset, error) {
 	ctx := context.TODO()
 	return o.GetSingleAssetWithContext(ctx, assetContractAddress, tokenID)
@@ -122,6 +164,10 @@ func (o Opensea) GetSingleAssetWithContext(ctx context.Context, assetContractAdd
 }
 func (o Opensea) GetAssetDetail(assetContractAddress string) (*Asset, error) {
 	ctx := context.TODO()
+	return o.GetAssetDetailWithContext(ctx, assetContractAddress)
+}
+
+func (o Opensea) GetAssetDetailWithContext(ctx context.Context, assetContractAddress string) (*Asset, error) {
 	path := fmt.Sprintf("/api/v1/assets?asset_contract_address=%s&limit=1", assetContractAddress)
 	b, err := o.GetPath(ctx, path)
 	if err != nil {
@@ -139,8 +185,25 @@ func (o Opensea) GetAssetDetail(assetContractAddress string) (*Asset, error) {
 	}
 }
 
+func (o Opensea) chain() Chain {
+	if o.Chain == "" {
+		return ChainEthereum
+	}
+	return o.Chain
+}
+
+// GetPath GETs path from the API. Concurrent calls for the same path share one request.
 func (o Opensea) GetPath(ctx context.Context, path string) ([]byte, error) {
-	return o.getURL(ctx, o.API+path)
+	get := func(ctx context.Context) ([]byte, error) {
+		if ttl, ok := o.cacheTTL(path); ok {
+			return o.getCached(ctx, o.API+path, ttl)
+		}
+		return o.getURL(ctx, o.API+path)
+	}
+	if o.flights == nil {
+		return get(ctx)
+	}
+	return o.flights.do(ctx, o.API+path, get)
 }
 func (o Opensea) PostPath(ctx context.Context, path string, data []byte) ([]byte, error) {
 	client := o.httpClient
@@ -148,6 +211,9 @@ func (o Opensea) PostPath(ctx context.Context, path string, data []byte) ([]byte
 	if o.proxy != "" {
 		target = o.proxy
 	}
+	if err := o.wait(ctx); err != nil {
+		return nil, err
+	}
 	req, err := http.NewRequestWithContext(ctx, "POST", target, bytes.NewBuffer(data))
 	if err != nil {
 		return nil, err
@@ -187,43 +253,70 @@ func (o Opensea) PostPath(ctx context.Context, path string, data []byte) ([]byte
 }
 
 func (o Opensea) getURL(ctx context.Context, url string) ([]byte, error) {
+	body, _, _, err := o.fetch(ctx, url, "")
+	return body, err
+}
+
+// fetch GETs url, revalidating with If-None-Match when etag is set. It returns the ETag of the response,
+// and notModified without a body when the server answered 304.
+func (o Opensea) fetch(ctx context.Context, url, etag string) (body []byte, newETag string, notModified bool, err error) {
 	client := o.httpClient
 	target := url
 	if o.proxy != "" {
 		target = o.proxy
 	}
+	if err := o.wait(ctx); err != nil {
+		return nil, "", false, err
+	}
 	req, err := http.NewRequestWithContext(ctx, "GET", target, nil)
+	if err != nil {
+		return nil, "", false, err
+	}
 	req.Header.Add("X-API-KEY", o.APIKey)
 	req.Header.Add("Accept", "application/json")
+	if etag != "" {
+		req.Header.Add("If-None-Match", etag)
+	}
 	if o.proxy != "" {
 		req.Header.Add("__ddd__", url)
 	}
 	resp, err := client.Do(req)
 	if err != nil {
-		return nil, err
+		return nil, "", false, err
 	}
 	defer resp.Body.Close()
 
-	body, err := ioutil.ReadAll(resp.Body)
+	if resp.StatusCode == http.StatusNotModified && etag != "" {
+		return nil, resp.Header.Get("ETag"), true, nil
+	}
+
+	body, err = ioutil.ReadAll(resp.Body)
 	if err != nil {
-		return nil, err
+		return nil, "", false, err
 	}
 
 	if resp.StatusCode != http.StatusOK {
 		e := new(errorResponse)
 		err = json.Unmarshal(body, e)
 		if err != nil {
-			return nil, fmt.Errorf("Backend returns status %d msg: %s", resp.StatusCode, string(body))
+			return nil, "", false, fmt.Errorf("Backend returns status %d msg: %s", resp.StatusCode, string(body))
 		}
 		if !e.Success {
 			e.Msg = resp.Status
-			return nil, e
+			return nil, "", false, e
 		}
 
-		return nil, fmt.Errorf("Backend returns status %d msg: %s", resp.StatusCode, string(body))
+		return nil, "", false, fmt.Errorf("Backend returns status %d msg: %s", resp.StatusCode, string(body))
 	}
 
-	return body, nil
+	return body, resp.Header.Get("ETag"), false, nil
+}
+
+func (o Opensea) wait(ctx context.Context) error {
+	if o.Limiter == nil {
+		return nil
+	}
+	return o.Limiter.Wait(ctx)
 }
 
 func (o Opensea) SetHttpClient(httpClient *http.Client) {
@@ -5,40 +5,190 @@ import (
 	"encoding/json"
 	"errors"
 	"math/big"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"golang.org/x/crypto/sha3"
 )
 
+// Number is a loosely parsed integer such as a salt or a count of basis points; prices are Amount.
 type Number string
 
-func (n Number) Big() *big.Int {
-	s := strings.Split(string(n), ".")
-	r, _ := new(big.Int).SetString(s[0], 0)
+// Big returns n as an integer, nil when it is null. A fraction other than zeros is an error, as in ParseAmount.
+func (n Number) Big() (*big.Int, error) {
+	a, err := ParseAmount(string(n), 0)
+	if err != nil {
+		return nil, err
+	}
+	return a.Int, nil
+}
+
+// Amount parses n as base units of a token with the given decimals, reporting malformed input.
+func (n Number) Amount(decimals int) (Amount, error) {
+	return ParseAmount(string(n), decimals)
+}
+
+// Int64 returns n as an int64, 0 when it is null, malformed or does not fit.
+func (n Number) Int64() int64 {
+	if r, err := n.Big(); err == nil && r != nil && r.IsInt64() {
+		return r.Int64()
+	}
+	return 0
+}
+
+// IsNull reports whether OpenSea sent null, or nothing, for n.
+func (n Number) IsNull() bool {
+	return n == ""
+}
+
+// UnmarshalJSON accepts a JSON string, a JSON number or null.
+func (n *Number) UnmarshalJSON(b []byte) error {
+	s, err := unquoteNumber(b)
+	if err != nil {
+		return err
+	}
+	*n = Number(s)
+	return nil
+}
+
+// MarshalBSONValue stores n as a Decimal128, or as a string when it has more digits than a Decimal128 holds.
+func (n Number) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	return numberToBSON(string(n))
+}
+
+func (n *Number) UnmarshalBSONValue(typ bsontype.Type, b []byte) error {
+	s, err := numberFromBSON(typ, b)
+	if err != nil {
+		return err
+	}
+	*n = Number(s)
+	return nil
+}
+
+// Decimal is an exact decimal such as a token price, kept as OpenSea sent it; empty when null.
+type Decimal string
+
+// Rat returns the exact value of d, nil when it is null or malformed.
+func (d Decimal) Rat() *big.Rat {
+	if d == "" {
+		return nil
+	}
+	r, ok := new(big.Rat).SetString(string(d))
+	if !ok {
+		return nil
+	}
 	return r
 }
 
+func (d Decimal) Float64() float64 {
+	f, _ := strconv.ParseFloat(string(d), 64)
+	return f
+}
+
+func (d Decimal) IsNull() bool {
+	return d == ""
+}
+
+// UnmarshalJSON accepts a JSON string, a JSON number or null.
+func (d *Decimal) UnmarshalJSON(b []byte) error {
+	s, err := unquoteNumber(b)
+	if err != nil {
+		return err
+	}
+	*d = Decimal(s)
+	return nil
+}
+
+func (d Decimal) MarshalJSON() ([]byte, error) {
+	if d.IsNull() {
+		return []byte("null"), nil
+	}
+	return []byte(strconv.Quote(string(d))), nil
+}
+
+// MarshalBSONValue stores d as a Decimal128, or as a string when it has more digits than a Decimal128 holds.
+func (d Decimal) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	return numberToBSON(string(d))
+}
+
+func (d *Decimal) UnmarshalBSONValue(typ bsontype.Type, b []byte) error {
+	s, err := numberFromBSON(typ, b)
+	if err != nil {
+		return err
+	}
+	*d = Decimal(s)
+	return nil
+}
+
+// unquoteNumber returns the text of a JSON number or string, and "" for null.
+func unquoteNumber(b []byte) (string, error) {
+	s := strings.TrimSpace(string(b))
+	switch {
+	case s == "null":
+		return "", nil
+	case len(s) > 0 && s[0] == '"':
+		return strconv.Unquote(s)
+	}
+	var num json.Number
+	if err := json.Unmarshal(b, &num); err != nil {
+		return "", err
+	}
+	return num.String(), nil
+}
+
+func numberToBSON(s string) (bsontype.Type, []byte, error) {
+	if s == "" {
+		return bsontype.Null, nil, nil
+	}
+	if d, err := primitive.ParseDecimal128(s); err == nil && d.String() == s {
+		return bson.MarshalValue(d)
+	}
+	return bson.MarshalValue(s)
+}
+
+// numberFromBSON returns the text of a BSON number or string, and "" for null.
+func numberFromBSON(typ bsontype.Type, b []byte) (string, error) {
+	v := bson.RawValue{Type: typ, Value: b}
+	switch typ {
+	case bsontype.Null, bsontype.Undefined:
+		return "", nil
+	case bsontype.Decimal128:
+		return v.Decimal128().String(), nil
+	case bsontype.String:
+		return v.StringValue(), nil
+	case bsontype.Int32, bsontype.Int64:
+		i, _ := v.AsInt64OK()
+		return strconv.FormatInt(i, 10), nil
+	case bsontype.Double:
+		return strconv.FormatFloat(v.Double(), 'f', -1, 64), nil
+	}
+	return "", errors.New("Invalid number: cannot decode BSON " + typ.String())
+}
+
+// Address is an Ethereum address, kept in lower case so that it compares and keys maps consistently.
+// Hex renders it in EIP-55 checksum form.
 type Address string
 
 const NullAddress Address = "0x0000000000000000000000000000000000000000"
 
+// IsHexAddress reports whether s is 0x followed by 40 hex characters, in any case.
 func IsHexAddress(s string) bool {
-	if s == "0x0" {
-		return true
-	}
-	if s[0:2] != "0x" {
+	if len(s) != 2+40 {
 		return false
 	}
-	addressLength := 2 + 40
-	if len(s) != addressLength {
+	if s[0:2] != "0x" && s[0:2] != "0X" {
 		return false
 	}
-	if !isHex(s[2:]) {
-		return false
-	}
-	return true
+	return isHex(s[2:])
 }
 
+// ParseAddress validates address; a mixed case address must carry a valid EIP-55 checksum.
+// An empty string is the NullAddress.
 func ParseAddress(address string) (Address, error) {
 	if address == "" {
 		return NullAddress, nil
@@ -46,13 +196,55 @@ func ParseAddress(address string) (Address, error) {
 	if !IsHexAddress(address) {
 		return "", errors.New("Invalid address: " + address)
 	}
-	return Address(strings.ToLower(address)), nil
+	a := Address("0x" + strings.ToLower(address[2:]))
+	digits := address[2:]
+	if digits != strings.ToLower(digits) && digits != strings.ToUpper(digits) && a.Hex() != "0x"+digits {
+		return "", errors.New("Invalid address checksum: " + address)
+	}
+	return a, nil
+}
+
+// AddressFromBytes returns the address of a 20 byte array.
+func AddressFromBytes(b [20]byte) Address {
+	return Address("0x" + hex.EncodeToString(b[:]))
 }
 
 func (a Address) String() string {
 	return string(a)
 }
 
+// Hex returns the EIP-55 mixed case checksum form of the address.
+func (a Address) Hex() string {
+	lower := strings.ToLower(strings.TrimPrefix(a.String(), "0x"))
+	h := sha3.NewLegacyKeccak256()
+	h.Write([]byte(lower))
+	sum := h.Sum(nil)
+	ret := []byte(lower)
+	for i, c := range ret {
+		if c < 'a' || c > 'f' {
+			continue
+		}
+		nibble := sum[i/2]
+		if i%2 == 0 {
+			nibble >>= 4
+		}
+		if nibble&0xf >= 8 {
+			ret[i] = c - 'a' + 'A'
+		}
+	}
+	return "0x" + string(ret)
+}
+
+// Bytes returns the 20 bytes of the address, zero if it is malformed.
+func (a Address) Bytes() [20]byte {
+	var ret [20]byte
+	b, err := hex.DecodeString(strings.TrimPrefix(a.String(), "0x"))
+	if err == nil && len(b) == len(ret) {
+		copy(ret[:], b)
+	}
+	return ret
+}
+
 func (a Address) IsNullAddress() bool {
 	if a.String() == NullAddress.String() {
 		return true
@@ -61,17 +253,15 @@ func (a Address) IsNullAddress() bool {
 }
 
 func (a *Address) UnmarshalJSON(b []byte) error {
-	var s string
-	var err error
 	if string(b) == "null" {
-		s = NullAddress.String()
-	} else {
-		s, err = strconv.Unquote(string(b))
+		*a = NullAddress
+		return nil
 	}
+	s, err := strconv.Unquote(string(b))
 	if err != nil {
 		return err
 	}
-	*a, _ = ParseAddress(s)
+	*a, err = ParseAddress(s)
 	return err
 }
 
@@ -80,6 +270,23 @@ func (a Address) MarshalJSON() ([]byte, error) {
 	return []byte(s), nil
 }
 
+func (a Address) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	return bson.MarshalValue(a.String())
+}
+
+func (a *Address) UnmarshalBSONValue(typ bsontype.Type, b []byte) error {
+	switch typ {
+	case bsontype.Null, bsontype.Undefined:
+		*a = NullAddress
+		return nil
+	case bsontype.String:
+		var err error
+		*a, err = ParseAddress(bson.RawValue{Type: typ, Value: b}.StringValue())
+		return err
+	}
+	return errors.New("Invalid address: cannot decode BSON " + typ.String())
+}
+
 type Bytes []byte
 
 func (by Bytes) Bytes32() [32]byte {
@@ -109,36 +316,172 @@ func (by Bytes) MarshalJSON() ([]byte, error) {
 	return []byte(s), nil
 }
 
+// MarshalBSONValue stores the bytes as BSON binary data.
+func (by Bytes) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	return bson.MarshalValue(primitive.Binary{Data: []byte(by)})
+}
+
+// UnmarshalBSONValue accepts binary data or a 0x prefixed hex string.
+func (by *Bytes) UnmarshalBSONValue(typ bsontype.Type, b []byte) error {
+	v := bson.RawValue{Type: typ, Value: b}
+	switch typ {
+	case bsontype.Null, bsontype.Undefined:
+		*by = nil
+	case bsontype.Binary:
+		_, data := v.Binary()
+		*by = append(Bytes{}, data...)
+	case bsontype.String:
+		var err error
+		*by, err = hex.DecodeString(strings.TrimPrefix(v.StringValue(), "0x"))
+		return err
+	default:
+		return errors.New("Invalid bytes: cannot decode BSON " + typ.String())
+	}
+	return nil
+}
+
+// TimeNano is a UTC time decoded from any of the forms OpenSea uses: ISO-8601 with or without
+// fraction and offset, unix seconds as a number or a string, with or without fraction, and null.
+// Unix time 0, null and seconds past int64, which Seaport uses for orders that never expire, are the zero time.
 type TimeNano time.Time
 
+var timeLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02T15:04:05.999999999Z0700",
+	"2006-01-02 15:04:05.999999999",
+	"2006-01-02 15:04:05.999999999Z07:00",
+	"2006-01-02",
+}
+
+// ParseTime parses s as a timestamp in any of the forms TimeNano decodes.
+func ParseTime(s string) (TimeNano, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "null" {
+		return TimeNano{}, nil
+	}
+	if secs, frac := splitFraction(s); isDigits(secs) && (frac == "" || isDigits(frac)) {
+		n, err := strconv.ParseInt(secs, 10, 64)
+		if err != nil {
+			// Seaport orders that never expire end at uint256 max.
+			return TimeNano{}, nil
+		}
+		var nanos int64
+		if frac != "" {
+			nanos, _ = strconv.ParseInt((frac + "000000000")[:9], 10, 64)
+		}
+		if n == 0 && nanos == 0 {
+			return TimeNano{}, nil
+		}
+		return TimeNano(time.Unix(n, nanos).UTC()), nil
+	}
+	for _, layout := range timeLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return TimeNano(t.UTC()), nil
+		}
+	}
+	return TimeNano{}, errors.New("Invalid time: " + s)
+}
+
+// Unix returns the TimeNano of unix seconds, the zero time for 0.
+func Unix(secs int64) TimeNano {
+	if secs == 0 {
+		return TimeNano{}
+	}
+	return TimeNano(time.Unix(secs, 0).UTC())
+}
+
 func (t TimeNano) Time() time.Time {
 	return time.Time(t)
 }
 
-func (t *TimeNano) UnmarshalJSON(b []byte) error {
-	s, err := strconv.Unquote(string(b))
-	if err != nil {
-		return err
+func (t TimeNano) IsZero() bool {
+	return t.Time().IsZero()
+}
+
+// Unix returns unix seconds, 0 for the zero time.
+func (t TimeNano) Unix() int64 {
+	if t.IsZero() {
+		return 0
+	}
+	return t.Time().Unix()
+}
+
+func (t TimeNano) String() string {
+	if t.IsZero() {
+		return ""
 	}
-	ss := strings.Split(s, "+")
-	tt := time.Time{}
-	//tt, err = time.Parse("2006-01-02T15:04:05.999999", ss[0])
-	if strings.Contains(ss[0], ".") {
-		tt, err = time.Parse("2006-01-02T15:04:05.999999", ss[0])
-	} else {
-		tt, err = time.Parse("2006-01-02T15:04:05", ss[0])
+	return t.Time().Format(time.RFC3339Nano)
+}
+
+func (t *TimeNano) UnmarshalJSON(b []byte) error {
+	s := string(b)
+	if len(s) > 0 && s[0] == '"' {
+		var err error
+		if s, err = strconv.Unquote(s); err != nil {
+			return err
+		}
 	}
+	tt, err := ParseTime(s)
 	if err != nil {
 		return err
 	}
-	*t = TimeNano(tt)
+	*t = tt
 	return nil
 }
 
 func (t TimeNano) MarshalJSON() ([]byte, error) {
-	s := t.Time().Format("2006-01-02T15:04:05.999999")
-	s = strconv.Quote(s)
-	return []byte(s), nil
+	if t.IsZero() {
+		return []byte("null"), nil
+	}
+	return []byte(strconv.Quote(t.String())), nil
+}
+
+// MarshalBSONValue stores the time as a BSON date, which keeps millisecond precision.
+func (t TimeNano) MarshalBSONValue() (bsontype.Type, []byte, error) {
+	if t.IsZero() {
+		return bsontype.Null, nil, nil
+	}
+	return bson.MarshalValue(t.Time())
+}
+
+func (t *TimeNano) UnmarshalBSONValue(typ bsontype.Type, b []byte) error {
+	v := bson.RawValue{Type: typ, Value: b}
+	switch typ {
+	case bsontype.Null, bsontype.Undefined:
+		*t = TimeNano{}
+	case bsontype.DateTime:
+		*t = TimeNano(v.Time().UTC())
+	case bsontype.String:
+		tt, err := ParseTime(v.StringValue())
+		if err != nil {
+			return err
+		}
+		*t = tt
+	case bsontype.Int64, bsontype.Int32:
+		secs, _ := v.AsInt64OK()
+		*t = Unix(secs)
+	default:
+		return errors.New("Invalid time: cannot decode BSON " + typ.String())
+	}
+	return nil
+}
+
+// splitFraction splits s at its decimal point; frac is "" when it has none.
+func splitFraction(s string) (secs, frac string) {
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		return s[:i], s[i+1:]
+	}
+	return s, ""
+}
+
+func isDigits(s string) bool {
+	for _, c := range []byte(s) {
+		if c < '0' || c > '9' {
+			return false
+		}
+	}
+	return s != ""
 }
 
 type Account struct {
@@ -148,29 +491,143 @@ type Account struct {
 	Config        string  `json:"config" bson:"config"`
 	DiscordID     string  `json:"discord_id" bson:"discord_id"`
 }
-type Account2 struct {
-	User          int64   `json:"user" bson:"user"`
-	ProfileImgURL string  `json:"profile_img_url" bson:"profile_img_url"`
-	Address       Address `json:"address" bson:"address"`
-	Config        string  `json:"config" bson:"config"`
-	DiscordID     string  `json:"discord_id" bson:"discord_id"`
+
+// UnmarshalJSON also accepts a bare address, which is how the newer endpoints refer to accounts.
+func (a *Account) UnmarshalJSON(b []byte) error {
+	if len(b) > 0 && b[0] == '"' {
+		var addr Address
+		if err := json.Unmarshal(b, &addr); err != nil {
+			return err
+		}
+		*a = Account{Address: addr}
+		return nil
+	}
+	type account Account
+	return json.Unmarshal(b, (*account)(a))
 }
+
+// Account2 is the old name of Account, from when the endpoints returning the user as an id had their own type.
+//
+// Deprecated: use Account.
+type Account2 = Account
+
 type AccountFee struct {
-	Account    Account2 `json:"account" bson:"account"`
-	BasePoints Number   `json:"basis_points" bson:"basis_points"`
+	Account    Account `json:"account" bson:"account"`
+	BasePoints Number  `json:"basis_points" bson:"basis_points"`
 }
 
+// User is the OpenSea user behind an account. Depending on the endpoint it comes as an object,
+// as the bare user id, or as null, which leaves both fields empty.
 type User struct {
+	ID       int64  `json:"id,omitempty" bson:"id,omitempty"`
 	Username string `json:"username" bson:"username"`
 }
 
+func (u *User) UnmarshalJSON(b []byte) error {
+	s := strings.TrimSpace(string(b))
+	switch {
+	case s == "null":
+		*u = User{}
+		return nil
+	case strings.HasPrefix(s, "{"):
+		type user User
+		var v user
+		if err := json.Unmarshal(b, &v); err != nil {
+			return err
+		}
+		*u = User(v)
+		return nil
+	}
+	if uq, err := strconv.Unquote(s); err == nil {
+		s = uq
+	}
+	id, err := strconv.ParseInt(s, 10, 64)
+	if err != nil {
+		return errors.New("Invalid user: " + string(b))
+	}
+	*u = User{ID: id}
+	return nil
+}
+
+// UnmarshalBSONValue reads the embedded document stored for a User, and also the bare user id
+// and null that documents saved with the old Account2 type hold.
+func (u *User) UnmarshalBSONValue(typ bsontype.Type, b []byte) error {
+	v := bson.RawValue{Type: typ, Value: b}
+	switch typ {
+	case bsontype.Null, bsontype.Undefined:
+		*u = User{}
+	case bsontype.EmbeddedDocument:
+		type user User
+		var d user
+		if err := v.Unmarshal(&d); err != nil {
+			return err
+		}
+		*u = User(d)
+	case bsontype.Int32, bsontype.Int64:
+		id, _ := v.AsInt64OK()
+		*u = User{ID: id}
+	default:
+		return errors.New("Invalid user: cannot decode BSON " + typ.String())
+	}
+	return nil
+}
+
+func (u User) IsZero() bool {
+	return u.ID == 0 && u.Username == ""
+}
+
 type Trait struct {
 	TraitType   string          `json:"trait_type" bson:"trait_type"`
 	Value       json.RawMessage `json:"value" bson:"value"`
-	DisplayType interface{}     `json:"display_type" bson:"display_type"`
-	MaxValue    interface{}     `json:"max_value" bson:"max_value"`
+	DisplayType string          `json:"display_type" bson:"display_type"`
+	MaxValue    Decimal         `json:"max_value" bson:"max_value"`
 	TraitCount  int64           `json:"trait_count" bson:"trait_count"`
-	Order       interface{}     `json:"order" bson:"order"`
+	Order       Number          `json:"order" bson:"order"`
+}
+
+// ValueString returns the trait value as text, whether OpenSea sent it as a string or a number.
+func (t Trait) ValueString() string {
+	var s string
+	if err := json.Unmarshal(t.Value, &s); err == nil {
+		return s
+	}
+	return strings.TrimSpace(string(t.Value))
+}
+
+// TraitCounts is the trait distribution of a collection: trait type -> value -> number of tokens.
+// Numeric traits, which OpenSea reports as a {"min": x, "max": y} range, are left out.
+type TraitCounts map[string]map[string]int64
+
+func (tc *TraitCounts) UnmarshalJSON(b []byte) error {
+	raw := map[string]map[string]json.Number{}
+	if err := json.Unmarshal(b, &raw); err != nil {
+		return err
+	}
+	*tc = TraitCounts{}
+	for traitType, values := range raw {
+		if isTraitRange(values) {
+			continue
+		}
+		counts := make(map[string]int64, len(values))
+		for value, n := range values {
+			c, err := n.Int64()
+			if err != nil {
+				return err
+			}
+			counts[value] = c
+		}
+		(*tc)[traitType] = counts
+	}
+	return nil
+}
+
+func isTraitRange(values map[string]json.Number) bool {
+	if len(values) != 2 {
+		return false
+	}
+	_, hasMin := values["min"]
+	_, hasMax := values["max"]
+	return hasMin && hasMax
 }
 
 type Value struct {
@@ -197,7 +654,49 @@ func isHex(str string) bool {
 }
 
 type AssetResponse struct {
-	Assets []Asset `json:"assets" bson:"assets"`
+	Next     string  `json:"next" bson:"next"`
+	Previous string  `json:"previous" bson:"previous"`
+	Assets   []Asset `json:"assets" bson:"assets"`
+}
+
+type GetAssetsParams struct {
+	Owner                string
+	AssetContractAddress string
+	TokenIDs             []string
+	CollectionSlug       string
+	OrderDirection       string //asc,desc
+	IncludeOrders        bool
+	Cursor               string
+	Limit                int
+}
+
+func (p GetAssetsParams) Encode() string {
+	q := url.Values{}
+	if p.Owner != "" {
+		q.Set("owner", p.Owner)
+	}
+	if p.AssetContractAddress != "" {
+		q.Set("asset_contract_address", p.AssetContractAddress)
+	}
+	for _, v := range p.TokenIDs {
+		q.Add("token_ids", v)
+	}
+	if p.CollectionSlug != "" {
+		q.Set("collection_slug", p.CollectionSlug)
+	}
+	if p.OrderDirection != "" {
+		q.Set("order_direction", p.OrderDirection)
+	}
+	if p.IncludeOrders {
+		q.Set("include_orders", "true")
+	}
+	if p.Cursor != "" {
+		q.Set("cursor", p.Cursor)
+	}
+	if p.Limit != 0 {
+		q.Set("limit", strconv.Itoa(p.Limit))
+	}
+	return q.Encode()
 }
 
 type Asset struct {
@@ -220,7 +719,8 @@ type Asset struct {
 	Collection           *Collection    `json:"collection" bson:"collection"`
 	Decimals             int64          `json:"decimals" bson:"decimals"`
 	TokenMetadata        string         `json:"token_metadata" bson:"token_metadata"`
-	Traits               interface{}    `json:"traits" bson:"traits"`
+	Traits               []Trait        `json:"traits" bson:"traits"`
+	LastSale             *Event         `json:"last_sale" bson:"last_sale"`
 }
 type AssetBundle struct {
 	Maker         *Account       `json:"maker" bson:"maker"`
@@ -232,7 +732,7 @@ type AssetBundle struct {
 	Collection    *Collection    `json:"collection" bson:"collection"`
 	AssetContract *AssetContract `json:"asset_contract" bson:"asset_contract"`
 	Permalink     string         `json:"permalink" bson:"permalink"`
-	SellOrders    interface{}    `json:"sell_orders" bson:"sell_orders"`
+	SellOrders    []*Order       `json:"sell_orders" bson:"sell_orders"`
 }
 
 type AssetContract struct {
@@ -241,11 +741,11 @@ type AssetContract struct {
 	CreatedDate                 string      `json:"created_date" bson:"created_date"`
 	Name                        string      `json:"name" bson:"name"`
 	NftVersion                  string      `json:"nft_version" bson:"nft_version"`
-	OpenseaVersion              interface{} `json:"opensea_version" bson:"opensea_version"`
+	OpenseaVersion              string      `json:"opensea_version" bson:"opensea_version"`
 	Owner                       int64       `json:"owner" bson:"owner"`
 	SchemaName                  string      `json:"schema_name" bson:"schema_name"`
 	Symbol                      string      `json:"symbol" bson:"symbol"`
-	TotalSupply                 interface{} `json:"total_supply" bson:"total_supply"`
+	TotalSupply                 Number      `json:"total_supply" bson:"total_supply"`
 	Description                 string      `json:"description" bson:"description"`
 	ExternalLink                string      `json:"external_link" bson:"external_link"`
 	ImageURL                    string      `json:"image_url" bson:"image_url"`
@@ -262,36 +762,42 @@ type AssetContract struct {
 }
 
 type Collection struct {
-	BannerImageUrl              string      `json:"banner_image_url" bson:"banner_image_url"`
-	ChatUrl                     string      `json:"chat_url" bson:"chat_url"`
-	CreatedDate                 string      `json:"created_date" bson:"created_date"`
-	DefaultToFiat               bool        `json:"default_to_fiat" bson:"default_to_fiat"`
-	Description                 string      `json:"description" bson:"description"`
-	DevBuyerFeeBasisPoints      interface{} `json:"dev_buyer_fee_basis_points" bson:"dev_buyer_fee_basis_points"`
-	DevSellerFeeBasisPoints     interface{} `json:"dev_seller_fee_basis_points" bson:"dev_seller_fee_basis_points"`
-	DiscordUrl                  string      `json:"discord_url" bson:"discord_url"`
-	DisplayData                 interface{} `json:"display_data" bson:"display_data"`
-	ExternalUrl                 string      `json:"external_url" bson:"external_url"`
-	Featured                    bool        `json:"featured" bson:"featured"`
-	FeaturedImageUrl            string      `json:"featured_image_url" bson:"featured_image_url"`
-	Hidden                      bool        `json:"hidden" bson:"hidden"`
-	SafelistRequestStatus       string      `json:"safelist_request_status" bson:"safelist_request_status"`
-	ImageUrl                    string      `json:"image_url" bson:"image_url"`
-	IsSubjectToWhitelist        bool        `json:"is_subject_to_whitelist" bson:"is_subject_to_whitelist"`
-	LargeImageUrl               string      `json:"large_image_url" bson:"large_image_url"`
-	MediumUsername              string      `json:"medium_username" bson:"medium_username"`
-	Name                        string      `json:"name" bson:"name"`
-	OnlyProxiedTransfers        bool        `json:"only_proxied_transfers" bson:"only_proxied_transfers"`
-	OpenseaBuyerFeeBasisPoints  interface{} `json:"opensea_buyer_fee_basis_points" bson:"opensea_buyer_fee_basis_points"`
-	OpenseaSellerFeeBasisPoints interface{} `json:"opensea_seller_fee_basis_points" bson:"opensea_seller_fee_basis_points"`
-	PayoutAddress               string      `json:"payout_address" bson:"payout_address"`
-	RequireEmail                bool        `json:"require_email" bson:"require_email"`
-	ShortDescription            string      `json:"short_description" bson:"short_description"`
-	Slug                        string      `json:"slug" bson:"slug"`
-	TelegramUrl                 string      `json:"telegram_url" bson:"telegram_url"`
-	TwitterUsername             string      `json:"twitter_username" bson:"twitter_username"`
-	InstagramUsername           string      `json:"instagram_username" bson:"instagram_username"`
-	WikiUrl                     string      `json:"wiki_url" bson:"wiki_url"`
+	BannerImageUrl              string       `json:"banner_image_url" bson:"banner_image_url"`
+	ChatUrl                     string       `json:"chat_url" bson:"chat_url"`
+	CreatedDate                 string       `json:"created_date" bson:"created_date"`
+	DefaultToFiat               bool         `json:"default_to_fiat" bson:"default_to_fiat"`
+	Description                 string       `json:"description" bson:"description"`
+	DevBuyerFeeBasisPoints      Number       `json:"dev_buyer_fee_basis_points" bson:"dev_buyer_fee_basis_points"`
+	DevSellerFeeBasisPoints     Number       `json:"dev_seller_fee_basis_points" bson:"dev_seller_fee_basis_points"`
+	DiscordUrl                  string       `json:"discord_url" bson:"discord_url"`
+	DisplayData                 *DisplayData `json:"display_data" bson:"display_data"`
+	ExternalUrl                 string       `json:"external_url" bson:"external_url"`
+	Featured                    bool         `json:"featured" bson:"featured"`
+	FeaturedImageUrl            string       `json:"featured_image_url" bson:"featured_image_url"`
+	Hidden                      bool         `json:"hidden" bson:"hidden"`
+	SafelistRequestStatus       string       `json:"safelist_request_status" bson:"safelist_request_status"`
+	ImageUrl                    string       `json:"image_url" bson:"image_url"`
+	IsSubjectToWhitelist        bool         `json:"is_subject_to_whitelist" bson:"is_subject_to_whitelist"`
+	LargeImageUrl               string       `json:"large_image_url" bson:"large_image_url"`
+	MediumUsername              string       `json:"medium_username" bson:"medium_username"`
+	Name                        string       `json:"name" bson:"name"`
+	OnlyProxiedTransfers        bool         `json:"only_proxied_transfers" bson:"only_proxied_transfers"`
+	OpenseaBuyerFeeBasisPoints  Number       `json:"opensea_buyer_fee_basis_points" bson:"opensea_buyer_fee_basis_points"`
+	OpenseaSellerFeeBasisPoints Number       `json:"opensea_seller_fee_basis_points" bson:"opensea_seller_fee_basis_points"`
+	PayoutAddress               string       `json:"payout_address" bson:"payout_address"`
+	RequireEmail                bool         `json:"require_email" bson:"require_email"`
+	ShortDescription            string       `json:"short_description" bson:"short_description"`
+	Slug                        string       `json:"slug" bson:"slug"`
+	TelegramUrl                 string       `json:"telegram_url" bson:"telegram_url"`
+	TwitterUsername             string       `json:"twitter_username" bson:"twitter_username"`
+	InstagramUsername           string       `json:"instagram_username" bson:"instagram_username"`
+	WikiUrl                     string       `json:"wiki_url" bson:"wiki_url"`
+}
+
+// DisplayData is how OpenSea shows the cards of a collection.
+type DisplayData struct {
+	CardDisplayStyle string   `json:"card_display_style" bson:"card_display_style"`
+	Images           []string `json:"images" bson:"images"`
 }
 
 type StatResponse struct {
@@ -299,27 +805,27 @@ type StatResponse struct {
 }
 
 type Stat struct {
-	OneDayVolume          float64 `json:"one_day_volume" bson:"one_day_volume"`
-	OneDayChange          float64 `json:"one_day_change" bson:"one_day_change"`
-	OneDaySales           float64 `json:"one_day_sales" bson:"one_day_sales"`
-	OneDayAveragePrice    float64 `json:"one_day_average_price" bson:"one_day_average_price"`
-	SevenDayVolume        float64 `json:"seven_day_volume" bson:"seven_day_volume"`
-	SevenDayChange        float64 `json:"seven_day_change" bson:"seven_day_change"`
-	SevenDaySales         float64 `json:"seven_day_sales" bson:"seven_day_sales"`
-	SevenDayAveragePrice  float64 `json:"seven_day_average_price" bson:"seven_day_average_price"`
-	ThirtyDayVolume       float64 `json:"thirty_day_volume" bson:"thirty_day_volume"`
-	ThirtyDayChange       float64 `json:"thirty_day_change" bson:"thirty_day_change"`
-	ThirtyDaySales        float64 `json:"thirty_day_sales" bson:"thirty_day_sales"`
-	ThirtyDayAveragePrice float64 `json:"thirty_day_average_price" bson:"thirty_day_average_price"`
-	TotalVolume           float64 `json:"total_volume" bson:"total_volume"`
-	TotalSales            float64 `json:"total_sales" bson:"total_sales"`
-	TotalSupply           float64 `json:"total_supply" bson:"total_supply"`
-	Count                 float64 `json:"count" bson:"count"`
-	NumOwners             float64 `json:"num_owners" bson:"num_owners"`
-	AveragePrice          float64 `json:"average_price" bson:"average_price"`
-	NumReports            float64 `json:"num_reports" bson:"num_reports"`
-	MarketCap             float64 `json:"market_cap" bson:"market_cap"`
-	FloorPrice            float64 `json:"floor_price" bson:"floor_price"`
+	OneDayVolume          EtherAmount `json:"one_day_volume" bson:"one_day_volume"`
+	OneDayChange          float64     `json:"one_day_change" bson:"one_day_change"`
+	OneDaySales           float64     `json:"one_day_sales" bson:"one_day_sales"`
+	OneDayAveragePrice    EtherAmount `json:"one_day_average_price" bson:"one_day_average_price"`
+	SevenDayVolume        EtherAmount `json:"seven_day_volume" bson:"seven_day_volume"`
+	SevenDayChange        float64     `json:"seven_day_change" bson:"seven_day_change"`
+	SevenDaySales         float64     `json:"seven_day_sales" bson:"seven_day_sales"`
+	SevenDayAveragePrice  EtherAmount `json:"seven_day_average_price" bson:"seven_day_average_price"`
+	ThirtyDayVolume       EtherAmount `json:"thirty_day_volume" bson:"thirty_day_volume"`
+	ThirtyDayChange       float64     `json:"thirty_day_change" bson:"thirty_day_change"`
+	ThirtyDaySales        float64     `json:"thirty_day_sales" bson:"thirty_day_sales"`
+	ThirtyDayAveragePrice EtherAmount `json:"thirty_day_average_price" bson:"thirty_day_average_price"`
+	TotalVolume           EtherAmount `json:"total_volume" bson:"total_volume"`
+	TotalSales            float64     `json:"total_sales" bson:"total_sales"`
+	TotalSupply           float64     `json:"total_supply" bson:"total_supply"`
+	Count                 float64     `json:"count" bson:"count"`
+	NumOwners             float64     `json:"num_owners" bson:"num_owners"`
+	AveragePrice          EtherAmount `json:"average_price" bson:"average_price"`
+	NumReports            float64     `json:"num_reports" bson:"num_reports"`
+	MarketCap             EtherAmount `json:"market_cap" bson:"market_cap"`
+	FloorPrice            EtherAmount `json:"floor_price" bson:"floor_price"`
 }
 
 type CollectionSingleResponse struct {
@@ -333,7 +839,8 @@ type CollectionSingle struct {
 	Editors               []Address      `json:"editors" bson:"editors"`
 	PaymentTokens         []PaymentToken `json:"payment_tokens" bson:"payment_tokens"`
 	PrimaryAssetContracts []Contract     `json:"primary_asset_contracts" bson:"primary_asset_contracts"`
-	Traits                interface{}    `json:"traits" bson:"traits"`
+	Traits                TraitCounts    `json:"traits" bson:"traits"`
+	Stats                 Stat           `json:"stats" bson:"stats"`
 	Collection
 }
 
@@ -348,8 +855,8 @@ type ProtocolData struct {
 	Signature  string          `json:"signature"`
 }
 type OrderComponent struct {
-	Offerer                         string          `json:"offerer"`
-	Zone                            string          `json:"zone"`
+	Offerer                         Address         `json:"offerer"`
+	Zone                            Address         `json:"zone"`
 	ZoneHash                        string          `json:"zoneHash"`
 	StartTime                       Number          `json:"startTime"`
 	EndTime                         Number          `json:"endTime"`
@@ -357,21 +864,34 @@ type OrderComponent struct {
 	Salt                            Number          `json:"salt" bson:"salt"`
 	ConduitKey                      string          `json:"conduitKey"`
 	TotalOriginalConsiderationItems int             `json:"totalOriginalConsiderationItems"`
-	Counter                         interface{}     `json:"counter"`
+	Counter                         Number          `json:"counter"`
 	Nonce                           string          `json:"nonce"`
 	Offer                           []OfferItem     `json:"offer"`
 	Consideration                   []Consideration `json:"consideration"`
 }
+
+// StartTimeAt returns the start time of the order, which StartTime keeps as signed.
+func (c OrderComponent) StartTimeAt() TimeNano {
+	t, _ := ParseTime(string(c.StartTime))
+	return t
+}
+
+// EndTimeAt returns the end time of the order, the zero time for orders that never expire.
+func (c OrderComponent) EndTimeAt() TimeNano {
+	t, _ := ParseTime(string(c.EndTime))
+	return t
+}
+
 type OfferItem struct {
-	ItemType             ItemType `joon:"itemType"`
-	Token                string   `json:"token"`
+	ItemType             ItemType `json:"itemType"`
+	Token                Address  `json:"token"`
 	IdentifierOrCriteria string   `json:"identifierOrCriteria"`
 	StartAmount          Number   `json:"startAmount"`
 	EndAmount            Number   `json:"endAmount"`
 }
 type Consideration struct {
 	OfferItem
-	Recipient string `json:"recipient"`
+	Recipient Address `json:"recipient"`
 }
 
 type OrderType int8
@@ -5,7 +5,12 @@ import (
 	"github.com/stretchr/testify/assert"
 	"io/ioutil"
 	"log"
+	"strings"
 	"testing"
+	"time"
+
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
 )
 
 func TestAssetResponse(t *testing.T) {
@@ -55,3 +60,322 @@ func TestSingleCollectionResponse(t *testing.T) {
 
 	assert.NotNil(t, osColl)
 }
+
+func TestTraitCounts(t *testing.T) {
+	var tc TraitCounts
+	err := json.Unmarshal([]byte(`{"hat": {"cap": 3, "crown": 1}, "level": {"min": 1, "max": 99}}`), &tc)
+	assert.Nil(t, err)
+	assert.Equal(t, int64(3), tc["hat"]["cap"])
+	_, ok := tc["level"]
+	assert.False(t, ok)
+
+	tr := Trait{Value: json.RawMessage(`2854`)}
+	assert.Equal(t, "2854", tr.ValueString())
+	tr = Trait{Value: json.RawMessage(`"Issue 1"`)}
+	assert.Equal(t, "Issue 1", tr.ValueString())
+}
+
+func TestAccountUserShapes(t *testing.T) {
+	var accounts []Account
+	err := json.Unmarshal([]byte(`[
+		{"user": {"username": "MaFriends"}, "address": "0xbc0c08bc6c8e949d54db31eddc8ee809ef323ec3"},
+		{"user": 5215003, "address": "0xa590870e16288831ce9ebcea873396b37bc7565d"},
+		{"user": null, "address": "0x8de9c5a032463c561423387a9648c5c7bcc5bc90"},
+		"0x8D0cF15d459b98fcC84A56d86737F44FF2204751"
+	]`), &accounts)
+	assert.Nil(t, err)
+	assert.Equal(t, "MaFriends", accounts[0].User.Username)
+	assert.Equal(t, int64(5215003), accounts[1].User.ID)
+	assert.True(t, accounts[2].User.IsZero())
+	assert.Equal(t, Address("0x8d0cf15d459b98fcc84a56d86737f44ff2204751"), accounts[3].Address)
+
+	var u User
+	assert.NotNil(t, json.Unmarshal([]byte(`true`), &u))
+
+	// both shapes survive a round trip through the unified type
+	by, err := json.Marshal(accounts[1])
+	assert.Nil(t, err)
+	var back Account
+	assert.Nil(t, json.Unmarshal(by, &back))
+	assert.Equal(t, accounts[1], back)
+}
+
+func TestAccountUserLegacyBSON(t *testing.T) {
+	// AccountFee documents saved before Account and Account2 were unified
+	raw, err := bson.Marshal(bson.M{"fees": bson.A{
+		bson.M{"account": bson.M{"user": int64(5215003), "address": "0xa590870e16288831ce9ebcea873396b37bc7565d"}, "basis_points": "250"},
+		bson.M{"account": bson.M{"user": int32(42), "address": "0xa590870e16288831ce9ebcea873396b37bc7565d"}, "basis_points": "250"},
+		bson.M{"account": bson.M{"user": nil, "address": "0x8de9c5a032463c561423387a9648c5c7bcc5bc90"}, "basis_points": "100"},
+		bson.M{"account": bson.M{"user": bson.M{"username": "MaFriends"}, "address": "0xbc0c08bc6c8e949d54db31eddc8ee809ef323ec3"}, "basis_points": "100"},
+	}})
+	assert.Nil(t, err)
+	var d struct {
+		Fees []AccountFee `bson:"fees"`
+	}
+	assert.Nil(t, bson.Unmarshal(raw, &d))
+	assert.Equal(t, int64(5215003), d.Fees[0].Account.User.ID)
+	assert.Equal(t, int64(42), d.Fees[1].Account.User.ID)
+	assert.True(t, d.Fees[2].Account.User.IsZero())
+	assert.Equal(t, "MaFriends", d.Fees[3].Account.User.Username)
+
+	// and what the unified type writes reads back
+	raw, err = bson.Marshal(d)
+	assert.Nil(t, err)
+	var back struct {
+		Fees []AccountFee `bson:"fees"`
+	}
+	assert.Nil(t, bson.Unmarshal(raw, &back))
+	assert.Equal(t, d, back)
+
+	bad, _ := bson.Marshal(bson.M{"user": true})
+	var a Account
+	assert.NotNil(t, bson.Unmarshal(bad, &a))
+}
+
+func TestAddress(t *testing.T) {
+	// EIP-55 test vectors
+	for _, v := range []string{
+		"0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAed",
+		"0xfB6916095ca1df60bB79Ce92cE3Ea74c37c5d359",
+		"0xdbF03B407c01E7cD3CBea99509d93f8DDDC8C6FB",
+		"0xD1220A0cf47c7B9Be7A2E6BA89F429762e7b9aDb",
+	} {
+		a, err := ParseAddress(v)
+		assert.Nil(t, err)
+		assert.Equal(t, Address(strings.ToLower(v)), a)
+		assert.Equal(t, v, a.Hex())
+		assert.Equal(t, a, AddressFromBytes(a.Bytes()))
+	}
+
+	_, err := ParseAddress("0x5aAeb6053F3E94C9b9A09f33669435E7Ef1BeAeD")
+	assert.NotNil(t, err)
+	_, err = ParseAddress("0x5AAEB6053F3E94C9B9A09F33669435E7EF1BEAED")
+	assert.Nil(t, err)
+	for _, v := range []string{"0x0", "0", "0x", "5aaeb6053f3e94c9b9a09f33669435e7ef1beaed", "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaeg"} {
+		assert.False(t, IsHexAddress(v), v)
+		_, err = ParseAddress(v)
+		assert.NotNil(t, err, v)
+	}
+
+	var item Consideration
+	err = json.Unmarshal([]byte(`{"token":"0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed","recipient":null}`), &item)
+	assert.Nil(t, err)
+	assert.Equal(t, Address("0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed"), item.Token)
+	assert.True(t, item.Recipient.IsNullAddress())
+	err = json.Unmarshal([]byte(`{"token":"0x0"}`), &item)
+	assert.NotNil(t, err)
+}
+
+func TestTimeNano(t *testing.T) {
+	want := time.Date(2022, 8, 1, 12, 30, 5, 0, time.UTC)
+	for _, v := range []string{
+		`"2022-08-01T12:30:05"`,
+		`"2022-08-01T12:30:05Z"`,
+		`"2022-08-01T12:30:05.000000"`,
+		`"2022-08-01T12:30:05.000000+00:00"`,
+		`"2022-08-01T14:30:05+02:00"`,
+		`"2022-08-01T14:30:05+0200"`,
+		`"1659357005"`,
+		`1659357005`,
+	} {
+		var tn TimeNano
+		assert.Nil(t, json.Unmarshal([]byte(v), &tn), v)
+		assert.True(t, want.Equal(tn.Time()), v)
+		assert.Equal(t, time.UTC, tn.Time().Location(), v)
+	}
+
+	var tn TimeNano
+	for _, v := range []string{`1659357005.25`, `"1659357005.250"`} {
+		assert.Nil(t, json.Unmarshal([]byte(v), &tn), v)
+		assert.True(t, want.Add(250*time.Millisecond).Equal(tn.Time()), v)
+	}
+	assert.Nil(t, json.Unmarshal([]byte(`"115792089237316195423570985008687907853269984665640564039457584007913129639935"`), &tn))
+	assert.True(t, tn.IsZero())
+
+	assert.Nil(t, json.Unmarshal([]byte(`"2022-08-01T12:30:05.123456"`), &tn))
+	assert.Equal(t, 123456000, tn.Time().Nanosecond())
+	b, err := json.Marshal(tn)
+	assert.Nil(t, err)
+	assert.Equal(t, `"2022-08-01T12:30:05.123456Z"`, string(b))
+	var back TimeNano
+	assert.Nil(t, json.Unmarshal(b, &back))
+	assert.Equal(t, tn, back)
+
+	assert.Nil(t, json.Unmarshal([]byte(`null`), &tn))
+	assert.True(t, tn.IsZero())
+	b, _ = json.Marshal(tn)
+	assert.Equal(t, "null", string(b))
+	assert.NotNil(t, json.Unmarshal([]byte(`"yesterday"`), &tn))
+
+	doc := struct {
+		At    TimeNano `bson:"at"`
+		Never TimeNano `bson:"never"`
+	}{At: TimeNano(want)}
+	raw, err := bson.Marshal(doc)
+	assert.Nil(t, err)
+	assert.Equal(t, bsontype.DateTime, bson.Raw(raw).Lookup("at").Type)
+	doc.At, doc.Never = TimeNano{}, Unix(1)
+	assert.Nil(t, bson.Unmarshal(raw, &doc))
+	assert.True(t, want.Equal(doc.At.Time()))
+	assert.True(t, doc.Never.IsZero())
+}
+
+func TestOrderComponentTimes(t *testing.T) {
+	ord := loadListing(t)
+	params := ord.ProtocolData.Parameters
+	assert.Equal(t, ord.ListingTime.Unix(), params.StartTimeAt().Unix())
+	assert.Equal(t, ord.ExpirationTime.Unix(), params.EndTimeAt().Unix())
+}
+
+func TestOrderComponentNeverExpires(t *testing.T) {
+	b, err := ioutil.ReadFile("test-files/listings-v2-no-expiry.json")
+	assert.Nil(t, err)
+	var ord OrderV2
+	assert.Nil(t, json.Unmarshal(b, &ord))
+	params := ord.ProtocolData.Parameters
+	assert.Equal(t, int64(1655337245), params.StartTimeAt().Unix())
+	assert.True(t, params.EndTimeAt().IsZero())
+	assert.True(t, ord.ExpirationTime.IsZero())
+
+	ord.MarkedInvalid = false
+	assert.True(t, IsValidEthListing(&ord, params.StartTimeAt().Time().AddDate(10, 0, 0)))
+}
+
+func TestOrderComponentRoundTrip(t *testing.T) {
+	b, err := ioutil.ReadFile("test-files/listings-v2-no-expiry.json")
+	assert.Nil(t, err)
+	var raw struct {
+		ProtocolData struct {
+			Parameters map[string]json.RawMessage `json:"parameters"`
+		} `json:"protocol_data"`
+	}
+	assert.Nil(t, json.Unmarshal(b, &raw))
+	var ord OrderV2
+	assert.Nil(t, json.Unmarshal(b, &ord))
+
+	by, err := json.Marshal(ord.ProtocolData)
+	assert.Nil(t, err)
+	var back struct {
+		Parameters map[string]json.RawMessage `json:"parameters"`
+	}
+	assert.Nil(t, json.Unmarshal(by, &back))
+	// the signed times come back as OpenSea sent them, uint256 max included
+	assert.Equal(t, string(raw.ProtocolData.Parameters["startTime"]), string(back.Parameters["startTime"]))
+	assert.Equal(t, string(raw.ProtocolData.Parameters["endTime"]), string(back.Parameters["endTime"]))
+	assert.Contains(t, string(back.Parameters["offer"]), `"itemType":2`)
+
+	// and the order re-encodes to the same bytes
+	var again ProtocolData
+	assert.Nil(t, json.Unmarshal(by, &again))
+	by2, err := json.Marshal(again)
+	assert.Nil(t, err)
+	assert.Equal(t, string(by), string(by2))
+}
+
+func TestConcreteFieldVariants(t *testing.T) {
+	b, err := ioutil.ReadFile("test-files/opeansea-contract.json")
+	assert.Nil(t, err)
+	var contract Contract
+	assert.Nil(t, json.Unmarshal(b, &contract))
+	assert.Equal(t, int64(750), contract.DevSellerFeeBasisPoints)
+	assert.True(t, contract.TotalSupply.IsNull())
+	assert.Equal(t, "", contract.OpenseaVersion)
+	assert.Equal(t, int64(250), contract.Collection.OpenseaSellerFeeBasisPoints.Int64())
+
+	b, err = ioutil.ReadFile("test-files/opensea-collection-doodles.json")
+	assert.Nil(t, err)
+	var coll CollectionSingleResponse
+	assert.Nil(t, json.Unmarshal(b, &coll))
+	assert.Equal(t, "contain", coll.Collection.DisplayData.CardDisplayStyle)
+	assert.Equal(t, Decimal("1.0"), coll.Collection.PaymentTokens[0].EthPrice)
+	assert.Equal(t, "4191.22", coll.Collection.PaymentTokens[0].UsdPrice.Rat().FloatString(2))
+	assert.Equal(t, Number("0"), coll.Collection.PrimaryAssetContracts[0].TotalSupply)
+
+	b, err = ioutil.ReadFile("test-files/opensea-assets-collectibles.json")
+	assert.Nil(t, err)
+	var assets AssetResponse
+	assert.Nil(t, json.Unmarshal(b, &assets))
+	var serial *Trait
+	for _, a := range assets.Assets {
+		for i, v := range a.Traits {
+			if v.TraitType == "Serial Number" {
+				serial = &a.Traits[i]
+			}
+		}
+	}
+	if assert.NotNil(t, serial) {
+		assert.Equal(t, "number", serial.DisplayType)
+		assert.Equal(t, 10000.0, serial.MaxValue.Float64())
+		assert.True(t, serial.Order.IsNull())
+	}
+
+	ord := loadListing(t)
+	assert.Equal(t, Number("0"), ord.ProtocolData.Parameters.Counter)
+	assert.Equal(t, Number("0"), ord.MakerAssetBundle.Assets[0].AssetContract.TotalSupply)
+	assert.True(t, ord.TakerAssetBundle.Assets[0].AssetContract.TotalSupply.IsNull())
+
+	var n struct {
+		A, B, C, D Number
+		P          Decimal
+	}
+	assert.Nil(t, json.Unmarshal([]byte(`{"A": 12, "B": "12", "C": null, "D": 9999.0, "P": 0.000123}`), &n))
+	assert.Equal(t, int64(12), n.A.Int64())
+	assert.Equal(t, n.A, n.B)
+	assert.True(t, n.C.IsNull())
+	assert.Equal(t, int64(9999), n.D.Int64())
+	assert.Equal(t, "0.000123", n.P.Rat().FloatString(6))
+	out, _ := json.Marshal(n)
+	assert.Equal(t, `{"A":"12","B":"12","C":"","D":"9999.0","P":"0.000123"}`, string(out))
+}
+
+func TestModelBSON(t *testing.T) {
+	type doc struct {
+		Address Address `bson:"address"`
+		Salt    Number  `bson:"salt"`
+		Count   Number  `bson:"count"`
+		Null    Number  `bson:"null"`
+		Price   Decimal `bson:"price"`
+		Sig     Bytes   `bson:"sig"`
+	}
+	in := doc{
+		Address: "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed",
+		Salt:    "24446860302761739304752683030156737591518664810215442929812345678901234567890",
+		Count:   "9999.0",
+		Price:   "4191.22",
+		Sig:     Bytes{0xde, 0xad, 0xbe, 0xef},
+	}
+	raw, err := bson.Marshal(in)
+	assert.Nil(t, err)
+	r := bson.Raw(raw)
+	assert.Equal(t, bsontype.String, r.Lookup("address").Type)
+	assert.Equal(t, bsontype.String, r.Lookup("salt").Type)
+	assert.Equal(t, bsontype.Decimal128, r.Lookup("count").Type)
+	assert.Equal(t, bsontype.Null, r.Lookup("null").Type)
+	assert.Equal(t, bsontype.Decimal128, r.Lookup("price").Type)
+	assert.Equal(t, bsontype.Binary, r.Lookup("sig").Type)
+
+	var out doc
+	assert.Nil(t, bson.Unmarshal(raw, &out))
+	assert.Equal(t, in, out)
+
+	bad, _ := bson.Marshal(bson.M{"address": "0x0"})
+	assert.NotNil(t, bson.Unmarshal(bad, &out))
+
+	// a whole decoded response survives a trip through Mongo
+	b, err := ioutil.ReadFile("test-files/opensea-events.json")
+	assert.Nil(t, err)
+	var events AssetEventsResponse
+	assert.Nil(t, json.Unmarshal(b, &events))
+	raw, err = bson.Marshal(events)
+	assert.Nil(t, err)
+	assert.NotEqual(t, bsontype.EmbeddedDocument, bson.Raw(raw).Lookup("asset_events", "0", "created_date").Type)
+	var back AssetEventsResponse
+	assert.Nil(t, bson.Unmarshal(raw, &back))
+	assert.Equal(t, events.Previous, back.Previous)
+	assert.Equal(t, len(events.AssetEvents), len(back.AssetEvents))
+	e, eb := events.AssetEvents[0], back.AssetEvents[0]
+	assert.Equal(t, e.CreatedDate.Time().UnixMilli(), eb.CreatedDate.Time().UnixMilli())
+	assert.Equal(t, 0, e.TotalPrice.Cmp(eb.TotalPrice))
+	assert.Equal(t, e.Asset.AssetContract.Address, eb.Asset.AssetContract.Address)
+	assert.Equal(t, e.PaymentToken.UsdPrice, eb.PaymentToken.UsdPrice)
+}
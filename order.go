@@ -5,7 +5,10 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/url"
+	"sync"
 	"time"
+
+	"golang.org/x/time/rate"
 )
 
 type Order struct {
@@ -15,14 +18,14 @@ type Order struct {
 	CreatedDate       *TimeNano `json:"created_date" bson:"created_date"`
 	ClosingDate       *TimeNano `json:"closing_date" bson:"closing_date"`
 	ClosingExtendable bool      `json:"closing_extendable" bson:"closing_extendable"`
-	ExpirationTime    int64     `json:"expiration_time" bson:"expiration_time"`
-	ListingTime       int64     `json:"listing_time" bson:"listing_time"`
+	ExpirationTime    TimeNano  `json:"expiration_time" bson:"expiration_time"`
+	ListingTime       TimeNano  `json:"listing_time" bson:"listing_time"`
 	OrderHash         string    `json:"order_hash" bson:"order_hash"`
 	Metadata          Metadata  `json:"metadata" bson:"metadata"`
 	Exchange          Address   `json:"exchange" bson:"exchange"`
-	Maker             Account2  `json:"maker" bson:"maker"`
-	Taker             Account2  `json:"taker" bson:"taker"`
-	CurrentPrice      Number    `json:"current_price" bson:"current_price"`
+	Maker             Account   `json:"maker" bson:"maker"`
+	Taker             Account   `json:"taker" bson:"taker"`
+	CurrentPrice      Amount    `json:"current_price" bson:"current_price"`
 	// CurrentBounty        string               `json:"current_bounty" bson:"current_bounty"`
 	BountyMultiple     string    `json:"bounty_multiple" bson:"bounty_multiple"`
 	MakerRelayerFee    Number    `json:"maker_relayer_fee" bson:"maker_relayer_fee"`
@@ -30,7 +33,7 @@ type Order struct {
 	MakerProtocolFee   Number    `json:"maker_protocol_fee" bson:"maker_protocol_fee"`
 	TakerProtocolFee   Number    `json:"taker_protocol_fee" bson:"taker_protocol_fee"`
 	MakerReferrerFee   Number    `json:"maker_referrer_fee" bson:"maker_referrer_fee"`
-	FeeRecipient       Account2  `json:"fee_recipient" bson:"fee_recipient"`
+	FeeRecipient       Account   `json:"fee_recipient" bson:"fee_recipient"`
 	FeeMethod          FeeMethod `json:"fee_method" bson:"fee_method"`
 	Side               Side      `json:"side" bson:"side"` // 0 for buy orders and 1 for sell orders.
 	SaleKind           SaleKind  `json:"sale_kind" bson:"sale_kind"`
@@ -42,8 +45,8 @@ type Order struct {
 	StaticExtradata    string    `json:"static_extradata" bson:"static_extradata"`
 	PaymentToken       Address   `json:"payment_token" bson:"payment_token"`
 	// PaymentTokenContract PaymentTokenContract `json:"payment_token_contract" bson:"payment_token_contract"`
-	BasePrice       Number `json:"base_price" bson:"base_price"`
-	Extra           Number `json:"extra" bson:"extra"`
+	BasePrice       Amount `json:"base_price" bson:"base_price"`
+	Extra           Amount `json:"extra" bson:"extra"`
 	Quantity        string `json:"quantity" bson:"quantity"`
 	Salt            Number `json:"salt" bson:"salt"`
 	V               *uint8 `json:"v" bson:"v"`
@@ -67,11 +70,11 @@ type OrderV2 struct {
 	OrderHash        string        `json:"order_hash" bson:"order_hash"`
 	CreatedDate      *TimeNano     `json:"created_date" bson:"created_date"`
 	ClosingDate      *TimeNano     `json:"closing_date" bson:"closing_date"`
-	ExpirationTime   int64         `json:"expiration_time" bson:"expiration_time"`
-	ListingTime      int64         `json:"listing_time" bson:"listing_time"`
-	Maker            *Account2     `json:"maker" bson:"maker"`
-	Taker            *Account2     `json:"taker" bson:"taker"`
-	CurrentPrice     Number        `json:"current_price" bson:"current_price"`
+	ExpirationTime   TimeNano      `json:"expiration_time" bson:"expiration_time"`
+	ListingTime      TimeNano      `json:"listing_time" bson:"listing_time"`
+	Maker            *Account      `json:"maker" bson:"maker"`
+	Taker            *Account      `json:"taker" bson:"taker"`
+	CurrentPrice     Amount        `json:"current_price" bson:"current_price"`
 	MakerFees        []AccountFee  `json:"maker_fees" bson:"maker_fees"`
 	TakerFees        []AccountFee  `json:"taker_fees" bson:"taker_fees"`
 	Side             string        `json:"side" bson:"side"`
@@ -131,7 +134,7 @@ type MetadataAsset struct {
 }
 type OrderParams struct {
 	AssetContractAddress string   `json:"asset_contract_address"`
-	PaymentTokenAddress  string   `json:"payment_token_address"`
+	PaymentTokenAddress  string   `json:"payment_token_address"` // address, or symbol of a token in DefaultTokenRegistry
 	Maker                string   `json:"maker"`
 	Taker                string   `json:"taker"`
 	Owner                string   `json:"owner"`
@@ -158,10 +161,122 @@ type listingsResp struct {
 	Listings        []*Order   `json:"listings"`
 	SeaportListings []*OrderV2 `json:"seaport_listings"`
 }
-type listingsRespV2 struct {
-	Next     string     `json:"next"`
-	Previous string     `json:"previous"`
-	Orders   []*OrderV2 `json:"orders"`
+
+// OrdersV2Response is a page of Seaport listings or offers; Next is the cursor of the following page.
+type OrdersV2Response struct {
+	Next     string     `json:"next" bson:"next"`
+	Previous string     `json:"previous" bson:"previous"`
+	Orders   []*OrderV2 `json:"orders" bson:"orders"`
+}
+
+// OrdersV2Params selects Seaport orders by token, maker or taker.
+type OrdersV2Params struct {
+	AssetContractAddress string
+	TokenIDs             []string
+	Maker                string
+	Taker                string
+	OrderBy              string // created_date or eth_price
+	OrderDirection       string // asc or desc
+	Cursor               string
+	Limit                int
+}
+
+func (p OrdersV2Params) Encode() string {
+	q := url.Values{}
+	if p.AssetContractAddress != "" {
+		q.Set("asset_contract_address", p.AssetContractAddress)
+	}
+	for _, v := range p.TokenIDs {
+		q.Add("token_ids", v)
+	}
+	if p.Maker != "" {
+		q.Set("maker", p.Maker)
+	}
+	if p.Taker != "" {
+		q.Set("taker", p.Taker)
+	}
+	if p.OrderBy != "" {
+		q.Set("order_by", p.OrderBy)
+	}
+	if p.OrderDirection != "" {
+		q.Set("order_direction", p.OrderDirection)
+	}
+	if p.Cursor != "" {
+		q.Set("cursor", p.Cursor)
+	}
+	if p.Limit != 0 {
+		q.Set("limit", fmt.Sprintf("%d", p.Limit))
+	}
+	return q.Encode()
+}
+
+// GetListingsV2Page fetches one page of the Seaport listings matching params.
+func (o Opensea) GetListingsV2Page(ctx context.Context, params OrdersV2Params) (*OrdersV2Response, error) {
+	return o.ordersV2Page(ctx, "listings", params)
+}
+
+// GetOffersV2Page fetches one page of the Seaport offers matching params.
+func (o Opensea) GetOffersV2Page(ctx context.Context, params OrdersV2Params) (*OrdersV2Response, error) {
+	return o.ordersV2Page(ctx, "offers", params)
+}
+
+// WalkListingsV2 calls fn with the Seaport listings matching params, page after page, without keeping them.
+// It stops at the first error of fn.
+func (o Opensea) WalkListingsV2(ctx context.Context, params OrdersV2Params, fn func(*OrderV2) error) error {
+	return o.walkOrdersV2(ctx, "listings", params, fn)
+}
+
+// WalkOffersV2 is WalkListingsV2 for offers.
+func (o Opensea) WalkOffersV2(ctx context.Context, params OrdersV2Params, fn func(*OrderV2) error) error {
+	return o.walkOrdersV2(ctx, "offers", params, fn)
+}
+
+func (o Opensea) walkOrdersV2(ctx context.Context, side string, params OrdersV2Params, fn func(*OrderV2) error) error {
+	for {
+		page, err := o.ordersV2Page(ctx, side, params)
+		if err != nil {
+			return err
+		}
+		for _, v := range page.Orders {
+			if err := fn(v); err != nil {
+				return err
+			}
+		}
+		if page.Next == "" || len(page.Orders) == 0 {
+			return nil
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		params.Cursor = page.Next
+	}
+}
+
+func (o Opensea) ordersV2Page(ctx context.Context, side string, params OrdersV2Params) (*OrdersV2Response, error) {
+	b, err := o.GetPath(ctx, fmt.Sprintf("/v2/orders/%s/seaport/%s?%s", o.chain(), side, params.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	var res OrdersV2Response
+	if err := json.Unmarshal(b, &res); err != nil {
+		return nil, err
+	}
+	o.labelPrices(res.Orders)
+	return &res, nil
+}
+
+// labelPrices labels the current price of each order with the decimals of its payment token,
+// which the v2 endpoints send in base units without saying how many decimals they have.
+// Tokens DefaultTokenRegistry does not know on the chain of the client keep EtherDecimals.
+func (o Opensea) labelPrices(orders []*OrderV2) {
+	for _, v := range orders {
+		if v == nil {
+			continue
+		}
+		if t, ok := DefaultTokenRegistry.ByAddress(o.chain(), v.PaymentTokenAddress()); ok {
+			v.CurrentPrice = v.CurrentPrice.WithDecimals(t.Decimals)
+		}
+	}
 }
 
 func (o Opensea) GetOrders2(assetContractAddress string, listedAfter int64) ([]*Order, error) {
@@ -191,8 +306,12 @@ func (p ListingFulfillment) String() string {
 }
 
 func (o Opensea) GetListingFulfillment(listing ListingParam, fulfiller FulfillerParam) (*ListingFulfillment, error) {
-	path := "/v2/listings/fulfillment_data"
 	ctx := context.TODO()
+	return o.GetListingFulfillmentWithContext(ctx, listing, fulfiller)
+}
+
+func (o Opensea) GetListingFulfillmentWithContext(ctx context.Context, listing ListingParam, fulfiller FulfillerParam) (*ListingFulfillment, error) {
+	path := "/v2/listings/fulfillment_data"
 	mm := map[string]interface{}{
 		"listing":   listing,
 		"fulfiller": fulfiller,
@@ -213,39 +332,81 @@ func (o Opensea) GetListingFulfillment(listing ListingParam, fulfiller Fulfiller
 	return res, nil
 }
 func (o Opensea) GetActiveListingsV2(assetAddress string, tokenIds []string) ([]*OrderV2, error) {
-	path := fmt.Sprintf("/v2/orders/ethereum/seaport/listings?limit=50&asset_contract_address=%s", assetAddress)
+	ctx := context.TODO()
+	return o.GetActiveListingsV2WithContext(ctx, assetAddress, tokenIds)
+}
+
+func (o Opensea) GetActiveListingsV2WithContext(ctx context.Context, assetAddress string, tokenIds []string) ([]*OrderV2, error) {
+	path := fmt.Sprintf("/v2/orders/%s/seaport/listings?limit=50&asset_contract_address=%s", o.chain(), assetAddress)
 	for _, v := range tokenIds {
 		path += "&token_ids=" + v
 	}
-	ctx := context.TODO()
 	by, err := o.GetPath(ctx, path)
 	if err != nil {
 		return nil, err
 	}
-	var res listingsRespV2
+	var res OrdersV2Response
 	err = json.Unmarshal(by, &res)
 	if err != nil {
 		return nil, err
 	}
+	o.labelPrices(res.Orders)
 	return res.Orders, nil
 }
+
+// GetActiveListings is GetActiveListingsWithContext without a context.
+//
+// Deprecated: use GetActiveListingsBatch.
 func (o Opensea) GetActiveListings(assetAddress string, tokenIds []string, interval time.Duration) ([]*Order, error) {
-	var list []*Order
-	for _, tokenId := range tokenIds {
-		res, err := o.getActiveListings(assetAddress, tokenId)
-		if err != nil {
-			return nil, err
+	ctx := context.TODO()
+	return o.GetActiveListingsWithContext(ctx, assetAddress, tokenIds, interval)
+}
+
+// GetActiveListingsWithContext fetches the v1 listings of each token, one request per token, a few tokens
+// at a time under the client Limiter; a positive interval further spaces the requests. It returns the listings
+// of every token, in token order, or nil and a BatchError naming the tokens that failed.
+//
+// Deprecated: use GetActiveListingsBatch, which fetches the Seaport listings of up to MaxTokenIDsPerQuery
+// tokens per request and keeps the listings of the tokens that succeeded.
+func (o Opensea) GetActiveListingsWithContext(ctx context.Context, assetAddress string, tokenIds []string, interval time.Duration) ([]*Order, error) {
+	results := make([][]*Order, len(tokenIds))
+	errs := BatchError{}
+	var mu sync.Mutex
+	var pace *rate.Limiter
+	if interval > 0 {
+		pace = rate.NewLimiter(rate.Every(interval), 1)
+	}
+	runBatch(ctx, len(tokenIds), defaultConcurrency, func(i int) {
+		var res []*Order
+		var err error
+		if pace != nil {
+			err = pace.Wait(ctx)
 		}
-		list = append(list, res...)
-		if interval > 0 {
-			time.Sleep(interval)
+		if err == nil {
+			res, err = o.getActiveListings(ctx, assetAddress, tokenIds[i])
 		}
+		mu.Lock()
+		defer mu.Unlock()
+		if err != nil {
+			errs[tokenIds[i]] = err
+		}
+		results[i] = res
+	})
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if len(errs) > 0 {
+		return nil, errs
+	}
+	var list []*Order
+	for _, v := range results {
+		list = append(list, v...)
 	}
 	return list, nil
 }
-func (o Opensea) getActiveListings(assetAddress string, tokenId string) ([]*Order, error) {
+func (o Opensea) getActiveListings(ctx context.Context, assetAddress string, tokenId string) ([]*Order, error) {
 	path := fmt.Sprintf("/api/v1/asset/%s/%s/listings?limit=%d", assetAddress, tokenId, 50)
-	b, err := o.GetPath(context.Background(), path)
+	b, err := o.GetPath(ctx, path)
 	if err != nil {
 		return nil, err
 	}
@@ -261,8 +422,14 @@ func (o Opensea) getActiveListings(assetAddress string, tokenId string) ([]*Orde
 	return res.Listings, nil
 }
 func (o Opensea) GetOrders(params OrderParams, findAll bool) ([]*Order, error) {
+	ctx := context.TODO()
+	return o.GetOrdersByParamsWithContext(ctx, params, findAll)
+}
+
+// GetOrdersByParamsWithContext is GetOrders with a context; GetOrdersWithContext is the context form of GetOrders2.
+func (o Opensea) GetOrdersByParamsWithContext(ctx context.Context, params OrderParams, findAll bool) ([]*Order, error) {
 	if !findAll {
-		return o.getOrders(params)
+		return o.getOrders(ctx, params)
 	}
 	offset := 0
 	limit := 50
@@ -270,7 +437,7 @@ func (o Opensea) GetOrders(params OrderParams, findAll bool) ([]*Order, error) {
 	for {
 		params.Offset = fmt.Sprintf("%d", offset)
 		params.Limit = fmt.Sprintf("%d", limit)
-		ords, err := o.getOrders(params)
+		ords, err := o.getOrders(ctx, params)
 		if err != nil {
 			return nil, err
 		}
@@ -279,13 +446,19 @@ func (o Opensea) GetOrders(params OrderParams, findAll bool) ([]*Order, error) {
 			break
 		}
 		if params.Delay > 0 {
-			time.Sleep(time.Duration(params.Delay) * time.Millisecond)
+			select {
+			case <-time.After(time.Duration(params.Delay) * time.Millisecond):
+			case <-ctx.Done():
+				return nil, ctx.Err()
+			}
+		} else if err := ctx.Err(); err != nil {
+			return nil, err
 		}
 		offset += limit
 	}
 	return orders, nil
 }
-func (o Opensea) getOrders(params OrderParams) ([]*Order, error) {
+func (o Opensea) getOrders(ctx context.Context, params OrderParams) ([]*Order, error) {
 	q := url.Values{}
 	if params.Offset == "" {
 		q.Set("offset", "0")
@@ -311,7 +484,15 @@ func (o Opensea) getOrders(params OrderParams) ([]*Order, error) {
 		q.Set("asset_contract_address", params.AssetContractAddress)
 	}
 	if params.PaymentTokenAddress != "" {
-		q.Set("payment_token_address", params.PaymentTokenAddress)
+		token := params.PaymentTokenAddress
+		if !IsHexAddress(token) {
+			t, ok := DefaultTokenRegistry.BySymbol(o.chain(), token)
+			if !ok {
+				return nil, fmt.Errorf("unknown payment token %s on %s", token, o.chain())
+			}
+			token = t.Address.String()
+		}
+		q.Set("payment_token_address", token)
 	}
 	if params.Maker != "" {
 		q.Set("maker", params.Maker)
@@ -350,7 +531,7 @@ func (o Opensea) getOrders(params OrderParams) ([]*Order, error) {
 			path += fmt.Sprintf("&token_ids=%s", v)
 		}
 	}
-	b, err := o.GetPath(context.Background(), path)
+	b, err := o.GetPath(ctx, path)
 	if err != nil {
 		return nil, err
 	}
@@ -396,6 +577,9 @@ func (o Opensea) GetOrdersWithContext(ctx context.Context, assetContractAddress
 		if len(out.Orders) < limit {
 			break
 		}
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		offset += limit
 	}
 
@@ -1,9 +1,16 @@
 package opensea
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
 )
 
 func TestGetOrders(t *testing.T) {
@@ -60,3 +67,83 @@ func TestOrdersJson(t *testing.T) {
 	}
 	fmt.Println(ord.ID, string(by))
 }
+
+func TestGetOrdersStopsWhenCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		cancel()
+		orders := make([]*Order, 50)
+		for i := range orders {
+			orders[i] = &Order{}
+		}
+		json.NewEncoder(w).Encode(orderResp{Orders: orders})
+	}))
+	defer srv.Close()
+	o := Opensea{API: srv.URL, httpClient: srv.Client()}
+
+	_, err := o.GetOrdersByParamsWithContext(ctx, OrderParams{}, true)
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&calls))
+
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	_, err = o.GetOrdersByParamsWithContext(ctx, OrderParams{Delay: 60000}, true)
+	assert.True(t, errors.Is(err, context.Canceled))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&calls))
+}
+
+func TestGetOrdersPaymentToken(t *testing.T) {
+	var got string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got = r.URL.Query().Get("payment_token_address")
+		json.NewEncoder(w).Encode(orderResp{})
+	}))
+	defer srv.Close()
+	o := Opensea{API: srv.URL, httpClient: srv.Client()}
+
+	// any address passes through, registered or not
+	_, err := o.GetOrders(OrderParams{PaymentTokenAddress: "0x4d224452801aced8b2f0aebe155379bb5d594381"}, false)
+	assert.Nil(t, err)
+	assert.Equal(t, "0x4d224452801aced8b2f0aebe155379bb5d594381", got)
+
+	_, err = o.GetOrders(OrderParams{PaymentTokenAddress: "weth"}, false)
+	assert.Nil(t, err)
+	assert.Equal(t, "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2", got)
+
+	_, err = o.GetOrders(OrderParams{PaymentTokenAddress: "NOPE"}, false)
+	assert.EqualError(t, err, "unknown payment token NOPE on ethereum")
+}
+
+func TestListingsV2PriceDecimals(t *testing.T) {
+	usdc := loadListing(t)
+	usdc.OrderHash = "0x01"
+	usdc.ProtocolData.Parameters.Consideration[0].Token = testUSDC
+	usdc.ProtocolData.Parameters.Consideration[0].ItemType = ItemERC20
+	usdc.CurrentPrice, _ = ParseAmount("2500000000", EtherDecimals)
+	eth := listingAt(t, "0x02", "0.9")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(OrdersV2Response{Orders: []*OrderV2{usdc, eth}})
+	}))
+	defer srv.Close()
+	o := Opensea{API: srv.URL, httpClient: srv.Client()}
+
+	page, err := o.GetListingsV2Page(context.Background(), OrdersV2Params{})
+	assert.Nil(t, err)
+	assert.Equal(t, "2500", page.Orders[0].CurrentPrice.Units())
+	assert.Equal(t, "0.9", page.Orders[1].CurrentPrice.Units())
+
+	orders, err := o.GetActiveListingsV2WithContext(context.Background(), "0x9bfa45382268e4bacbd1175395728153dc5248f2", []string{"1998"})
+	assert.Nil(t, err)
+	assert.Equal(t, 6, orders[0].CurrentPrice.Decimals)
+
+	batch := o.GetActiveListingsBatch(context.Background(), "0x9bfa45382268e4bacbd1175395728153dc5248f2", []string{"1998"}, BatchOptions{})
+	assert.Equal(t, "2500", batch[0].Orders[0].CurrentPrice.Units())
+
+	// a chain where the token is unknown keeps the price in ether decimals
+	o.Chain = ChainRinkeby
+	page, err = o.GetListingsV2Page(context.Background(), OrdersV2Params{})
+	assert.Nil(t, err)
+	assert.Equal(t, EtherDecimals, page.Orders[0].CurrentPrice.Decimals)
+}
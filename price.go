@@ -0,0 +1,212 @@
+package opensea
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+)
+
+// USDDecimals is the precision of USD amounts returned by the PriceNormalizer.
+const USDDecimals = 6
+
+// TokenPrice is the price of one whole token in ETH and in USD. Either may be nil when unknown.
+type TokenPrice struct {
+	Eth *big.Rat
+	Usd *big.Rat
+}
+
+// PriceOracle looks up the current price of a payment token by its contract address,
+// NullAddress being ETH.
+type PriceOracle interface {
+	TokenPrice(ctx context.Context, token Address) (TokenPrice, error)
+}
+
+var ErrUnknownTokenPrice = errors.New("unknown token price")
+
+// StaticOracle is a PriceOracle with fixed prices, keyed by lower case token address.
+type StaticOracle map[Address]TokenPrice
+
+func (s StaticOracle) TokenPrice(ctx context.Context, token Address) (TokenPrice, error) {
+	if p, ok := s[Address(strings.ToLower(token.String()))]; ok {
+		return p, nil
+	}
+	return TokenPrice{}, ErrUnknownTokenPrice
+}
+
+// NewStaticOracle parses prices given as decimal strings, such as {"0x...": {"0.0005", "1.00"}}.
+func NewStaticOracle(prices map[string][2]string) (StaticOracle, error) {
+	s := StaticOracle{}
+	for addr, v := range prices {
+		a, err := ParseAddress(addr)
+		if err != nil {
+			return nil, err
+		}
+		var p TokenPrice
+		if v[0] != "" {
+			if p.Eth, err = parseRat(v[0]); err != nil {
+				return nil, err
+			}
+		}
+		if v[1] != "" {
+			if p.Usd, err = parseRat(v[1]); err != nil {
+				return nil, err
+			}
+		}
+		s[a] = p
+	}
+	return s, nil
+}
+
+// Prices returns the ETH and USD price OpenSea attached to the token, nil when missing.
+func (p PaymentToken) Prices() TokenPrice {
+	return TokenPrice{Eth: p.EthPrice.Rat(), Usd: p.UsdPrice.Rat()}
+}
+
+// NormalizedPrice is an amount of some payment token together with its value in ETH and USD.
+// Eth or Usd is nil when the price of the token is not known.
+type NormalizedPrice struct {
+	Amount Amount
+	Token  Address
+	Eth    *Amount
+	Usd    *Amount
+}
+
+// PriceNormalizer converts prices paid in any token to ETH and USD. It uses the prices OpenSea attaches
+// to payment tokens, and asks Oracle for what is missing. ETH is always worth one ETH.
+type PriceNormalizer struct {
+	Oracle PriceOracle
+	// Tokens gives the decimals of order and consideration amounts on Chain, DefaultTokenRegistry if nil.
+	Tokens *TokenRegistry
+	Chain  Chain
+	// PreferOracle asks the oracle first, for current rather than OpenSea's cached prices.
+	PreferOracle bool
+}
+
+func NewPriceNormalizer(oracle PriceOracle) *PriceNormalizer {
+	return &PriceNormalizer{Oracle: oracle, Chain: ChainEthereum}
+}
+
+// Normalize values amount of token; meta is the payment token OpenSea returned with it, if any.
+func (n *PriceNormalizer) Normalize(ctx context.Context, amount Amount, token Address, meta *PaymentToken) (NormalizedPrice, error) {
+	if token == "" {
+		token = NullAddress
+	}
+	np := NormalizedPrice{Amount: amount, Token: token}
+	price, err := n.tokenPrice(ctx, token, meta)
+	if err != nil {
+		return np, err
+	}
+	if price.Eth != nil {
+		v := convert(amount, price.Eth, EtherDecimals)
+		np.Eth = &v
+	}
+	if price.Usd != nil {
+		v := convert(amount, price.Usd, USDDecimals)
+		np.Usd = &v
+	}
+	return np, nil
+}
+
+// EventPrice values the total price of a sale or the amount of an offer.
+func (n *PriceNormalizer) EventPrice(ctx context.Context, e *Event) (NormalizedPrice, error) {
+	amount := e.TotalPrice
+	if amount.Int == nil {
+		amount = e.BidAmount
+	}
+	token := NullAddress
+	if e.PaymentToken != nil {
+		token = e.PaymentToken.Address
+	}
+	return n.Normalize(ctx, amount, token, e.PaymentToken)
+}
+
+// OrderPrice values the current price of an order, in the currency of its consideration.
+func (n *PriceNormalizer) OrderPrice(ctx context.Context, o *OrderV2) (NormalizedPrice, error) {
+	amount := o.CurrentPrice
+	if t, ok := n.tokens().ByAddress(n.Chain, o.PaymentTokenAddress()); ok {
+		amount = amount.WithDecimals(t.Decimals)
+	}
+	return n.Normalize(ctx, amount, o.PaymentTokenAddress(), nil)
+}
+
+// Consideration values each currency item of a Seaport consideration at its start amount;
+// NFT items are skipped.
+func (n *PriceNormalizer) Consideration(ctx context.Context, items []Consideration) ([]NormalizedPrice, error) {
+	var ret []NormalizedPrice
+	for _, v := range items {
+		if v.ItemType != ItemNative && v.ItemType != ItemERC20 {
+			continue
+		}
+		token := v.Token
+		decimals := EtherDecimals
+		if t, ok := n.tokens().ByAddress(n.Chain, token); ok {
+			decimals = t.Decimals
+		}
+		amount, err := v.StartAmount.Amount(decimals)
+		if err != nil {
+			return nil, err
+		}
+		np, err := n.Normalize(ctx, amount, token, nil)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, np)
+	}
+	return ret, nil
+}
+
+func (n *PriceNormalizer) tokens() *TokenRegistry {
+	if n.Tokens != nil {
+		return n.Tokens
+	}
+	return DefaultTokenRegistry
+}
+
+func (n *PriceNormalizer) tokenPrice(ctx context.Context, token Address, meta *PaymentToken) (TokenPrice, error) {
+	var price TokenPrice
+	if meta != nil && !n.PreferOracle {
+		price = meta.Prices()
+	}
+	if (price.Eth == nil || price.Usd == nil) && n.Oracle != nil {
+		p, err := n.Oracle.TokenPrice(ctx, token)
+		if err != nil && !errors.Is(err, ErrUnknownTokenPrice) {
+			return price, err
+		}
+		if price.Eth == nil {
+			price.Eth = p.Eth
+		}
+		if price.Usd == nil {
+			price.Usd = p.Usd
+		}
+	}
+	if meta != nil && n.PreferOracle {
+		fallback := meta.Prices()
+		if price.Eth == nil {
+			price.Eth = fallback.Eth
+		}
+		if price.Usd == nil {
+			price.Usd = fallback.Usd
+		}
+	}
+	if price.Eth == nil && token.IsNullAddress() {
+		price.Eth = big.NewRat(1, 1)
+	}
+	return price, nil
+}
+
+// convert multiplies amount by the price of one whole token, truncating to the given decimals.
+func convert(amount Amount, price *big.Rat, decimals int) Amount {
+	r := new(big.Rat).SetFrac(amount.Big(), pow10(amount.Decimals))
+	r.Mul(r, price)
+	r.Mul(r, new(big.Rat).SetInt(pow10(decimals)))
+	return Amount{Int: new(big.Int).Quo(r.Num(), r.Denom()), Decimals: decimals}
+}
+
+func parseRat(s string) (*big.Rat, error) {
+	r, ok := new(big.Rat).SetString(strings.TrimSpace(s))
+	if !ok {
+		return nil, errors.New("Invalid price: " + s)
+	}
+	return r, nil
+}
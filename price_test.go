@@ -0,0 +1,111 @@
+package opensea
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testWETH = "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2"
+	testUSDC = "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48"
+)
+
+func testOracle(t *testing.T) StaticOracle {
+	oracle, err := NewStaticOracle(map[string][2]string{
+		NullAddress.String(): {"1", "2000"},
+		testWETH:             {"1", "2000"},
+		testUSDC:             {"0.0005", "1"},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return oracle
+}
+
+func TestNormalizeEventWithTokenMetadata(t *testing.T) {
+	b, err := ioutil.ReadFile("test-files/opensea-events.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var resp AssetEventsResponse
+	assert.Nil(t, json.Unmarshal(b, &resp))
+
+	// no oracle, the prices come with the payment token
+	n := NewPriceNormalizer(nil)
+	for _, e := range resp.AssetEvents {
+		np, err := n.EventPrice(context.Background(), e)
+		assert.Nil(t, err)
+		assert.NotNil(t, np.Eth)
+		assert.NotNil(t, np.Usd)
+		assert.Equal(t, 0, np.Eth.Cmp(e.TotalPrice))
+	}
+}
+
+func TestNormalizeWithOracle(t *testing.T) {
+	n := NewPriceNormalizer(testOracle(t))
+	ctx := context.Background()
+
+	usdc, _ := ParseUnits("3000", 6)
+	np, err := n.Normalize(ctx, usdc, Address(testUSDC), nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "1.5", np.Eth.Units())
+	assert.Equal(t, "3000", np.Usd.Units())
+
+	e := &Event{
+		TotalPrice:   Ether("0.5"),
+		PaymentToken: &PaymentToken{Symbol: "WETH", Address: testWETH, Decimals: 18, EthPrice: "1.0", UsdPrice: "4196.81"},
+	}
+	np, err = n.EventPrice(ctx, e)
+	assert.Nil(t, err)
+	assert.Equal(t, "2098.405", np.Usd.Units())
+
+	n.PreferOracle = true
+	np, err = n.EventPrice(ctx, e)
+	assert.Nil(t, err)
+	assert.Equal(t, "1000", np.Usd.Units())
+
+	// unknown tokens are left unvalued rather than failing
+	np, err = n.Normalize(ctx, Ether("1"), "0x4d224452801aced8b2f0aebe155379bb5d594381", nil)
+	assert.Nil(t, err)
+	assert.Nil(t, np.Eth)
+	assert.Nil(t, np.Usd)
+}
+
+type wrappingOracle struct{ PriceOracle }
+
+func (w wrappingOracle) TokenPrice(ctx context.Context, token Address) (TokenPrice, error) {
+	p, err := w.PriceOracle.TokenPrice(ctx, token)
+	if err != nil {
+		return p, fmt.Errorf("oracle %s: %w", token, err)
+	}
+	return p, nil
+}
+
+func TestNormalizeWithWrappedUnknownPrice(t *testing.T) {
+	n := NewPriceNormalizer(wrappingOracle{testOracle(t)})
+	np, err := n.Normalize(context.Background(), Ether("1"), "0x4d224452801aced8b2f0aebe155379bb5d594381", nil)
+	assert.Nil(t, err)
+	assert.Nil(t, np.Eth)
+}
+
+func TestNormalizeOrder(t *testing.T) {
+	ord := loadListing(t)
+	n := NewPriceNormalizer(testOracle(t))
+
+	np, err := n.OrderPrice(context.Background(), ord)
+	assert.Nil(t, err)
+	assert.Equal(t, NullAddress, np.Token)
+	assert.Equal(t, "1", np.Eth.Units())
+	assert.Equal(t, "2000", np.Usd.Units())
+
+	items, err := n.Consideration(context.Background(), ord.ProtocolData.Parameters.Consideration)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(items))
+	assert.Equal(t, "0.94", items[0].Eth.Units())
+	assert.Equal(t, "50", items[1].Usd.Units())
+}
@@ -0,0 +1,249 @@
+// Package rarity scores the tokens of a collection from its trait distribution.
+package rarity
+
+import (
+	"errors"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jumpblock/go-opensea"
+)
+
+// TraitCountType is the synthetic trait type used when Options.TraitCount is set.
+const TraitCountType = "trait_count"
+
+// MissingValue is the value given to a trait type a token does not have when Options.Missing is set.
+const MissingValue = "none"
+
+type Method int
+
+const (
+	// StatisticalRarity scores a token by the inverse of the product of its trait probabilities.
+	StatisticalRarity Method = iota
+	// TraitRaritySum scores a token by the sum of the inverse probabilities of its traits.
+	TraitRaritySum
+	// InformationContent scores a token by the information content of its traits,
+	// normalised by the entropy of the collection, as OpenRarity does.
+	InformationContent
+)
+
+func (m Method) String() string {
+	switch m {
+	case StatisticalRarity:
+		return "statistical"
+	case TraitRaritySum:
+		return "trait_rarity_sum"
+	case InformationContent:
+		return "information_content"
+	}
+	return "method(" + strconv.Itoa(int(m)) + ")"
+}
+
+type Token struct {
+	ID     string
+	Traits map[string]string // trait type -> value, both lower case
+}
+
+func NewToken(id string, traits map[string]string) Token {
+	t := Token{ID: id, Traits: make(map[string]string, len(traits))}
+	for k, v := range traits {
+		t.Traits[normalize(k)] = normalize(v)
+	}
+	return t
+}
+
+func TokenFromAsset(a opensea.Asset) Token {
+	traits := make(map[string]string, len(a.Traits))
+	for _, v := range a.Traits {
+		traits[v.TraitType] = v.ValueString()
+	}
+	return NewToken(a.TokenID, traits)
+}
+
+// Distribution holds how many of Total tokens carry each trait value.
+type Distribution struct {
+	Total  int64
+	Counts opensea.TraitCounts
+}
+
+func NewDistribution(total int64, counts opensea.TraitCounts) *Distribution {
+	d := &Distribution{Total: total, Counts: opensea.TraitCounts{}}
+	for traitType, values := range counts {
+		for value, n := range values {
+			d.add(traitType, value, n)
+		}
+	}
+	return d
+}
+
+// FromCollection uses the trait counts OpenSea keeps for a collection and its total supply.
+func FromCollection(c opensea.CollectionSingle) *Distribution {
+	total := int64(c.Stats.TotalSupply)
+	if total == 0 {
+		total = int64(c.Stats.Count)
+	}
+	return NewDistribution(total, c.Traits)
+}
+
+// FromAssets counts the traits of a full crawl of a collection.
+func FromAssets(assets []opensea.Asset) *Distribution {
+	tokens := make([]Token, 0, len(assets))
+	for _, a := range assets {
+		tokens = append(tokens, TokenFromAsset(a))
+	}
+	return FromTokens(tokens)
+}
+
+func FromTokens(tokens []Token) *Distribution {
+	d := &Distribution{Total: int64(len(tokens)), Counts: opensea.TraitCounts{}}
+	for _, t := range tokens {
+		for traitType, value := range t.Traits {
+			d.add(traitType, value, 1)
+		}
+	}
+	return d
+}
+
+func (d *Distribution) add(traitType, value string, n int64) {
+	traitType, value = normalize(traitType), normalize(value)
+	values, ok := d.Counts[traitType]
+	if !ok {
+		values = map[string]int64{}
+		d.Counts[traitType] = values
+	}
+	values[value] += n
+}
+
+type Options struct {
+	Method Method
+	// TraitCount adds the number of traits a token has as an extra trait,
+	// distributed over the tokens being ranked.
+	TraitCount bool
+	// Missing treats the absence of a trait type as the value MissingValue,
+	// held by the tokens the distribution does not count for that type.
+	Missing bool
+}
+
+type Score struct {
+	TokenID string
+	Score   float64 // higher is rarer, whatever the method
+	Rank    int     // 1 is the rarest, tied scores share a rank
+}
+
+// Rank scores tokens against d and returns them rarest first.
+// Trait values that d does not know about are ignored.
+func Rank(d *Distribution, tokens []Token, opts Options) ([]Score, error) {
+	if d == nil || d.Total <= 0 {
+		return nil, errors.New("rarity: distribution has no tokens")
+	}
+	probs := d.probabilities(tokens, opts)
+
+	var entropy float64
+	if opts.Method == InformationContent {
+		for _, values := range probs {
+			for _, p := range values {
+				entropy -= p * math.Log2(p)
+			}
+		}
+	}
+
+	scores := make([]Score, 0, len(tokens))
+	for _, t := range tokens {
+		s := Score{TokenID: t.ID}
+		product := 1.0
+		for traitType, values := range probs {
+			p, ok := values[tokenValue(t, traitType, opts)]
+			if !ok {
+				continue
+			}
+			switch opts.Method {
+			case StatisticalRarity:
+				product *= p
+			case TraitRaritySum:
+				s.Score += 1 / p
+			case InformationContent:
+				s.Score -= math.Log2(p)
+			}
+		}
+		switch opts.Method {
+		case StatisticalRarity:
+			s.Score = 1 / product
+		case InformationContent:
+			if entropy > 0 {
+				s.Score /= entropy
+			}
+		}
+		scores = append(scores, s)
+	}
+
+	sort.SliceStable(scores, func(i, j int) bool {
+		if scores[i].Score != scores[j].Score {
+			return scores[i].Score > scores[j].Score
+		}
+		return scores[i].TokenID < scores[j].TokenID
+	})
+	for i := range scores {
+		if i > 0 && equal(scores[i].Score, scores[i-1].Score) {
+			scores[i].Rank = scores[i-1].Rank
+		} else {
+			scores[i].Rank = i + 1
+		}
+	}
+	return scores, nil
+}
+
+// probabilities returns trait type -> value -> share of the collection.
+func (d *Distribution) probabilities(tokens []Token, opts Options) map[string]map[string]float64 {
+	total := float64(d.Total)
+	probs := make(map[string]map[string]float64, len(d.Counts)+1)
+	for traitType, values := range d.Counts {
+		p := make(map[string]float64, len(values)+1)
+		var seen int64
+		for value, n := range values {
+			if n <= 0 {
+				continue
+			}
+			p[value] = float64(n) / total
+			seen += n
+		}
+		if opts.Missing && seen < d.Total {
+			p[MissingValue] = float64(d.Total-seen) / total
+		}
+		probs[traitType] = p
+	}
+	if opts.TraitCount && len(tokens) > 0 {
+		counts := map[string]int64{}
+		for _, t := range tokens {
+			counts[strconv.Itoa(len(t.Traits))]++
+		}
+		p := make(map[string]float64, len(counts))
+		for value, n := range counts {
+			p[value] = float64(n) / float64(len(tokens))
+		}
+		probs[TraitCountType] = p
+	}
+	return probs
+}
+
+func tokenValue(t Token, traitType string, opts Options) string {
+	if traitType == TraitCountType && opts.TraitCount {
+		return strconv.Itoa(len(t.Traits))
+	}
+	if v, ok := t.Traits[traitType]; ok {
+		return v
+	}
+	if opts.Missing {
+		return MissingValue
+	}
+	return ""
+}
+
+func normalize(s string) string {
+	return strings.ToLower(strings.TrimSpace(s))
+}
+
+func equal(a, b float64) bool {
+	return math.Abs(a-b) <= 1e-9*math.Max(math.Abs(a), math.Abs(b))
+}
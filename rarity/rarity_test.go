@@ -0,0 +1,122 @@
+package rarity
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"math"
+	"testing"
+
+	"github.com/jumpblock/go-opensea"
+	"github.com/stretchr/testify/assert"
+)
+
+func sampleTokens() []Token {
+	return []Token{
+		NewToken("1", map[string]string{"Hat": "Cap", "Eyes": "Blue"}),
+		NewToken("2", map[string]string{"Hat": "Cap", "Eyes": "Blue"}),
+		NewToken("3", map[string]string{"Hat": "Crown", "Eyes": "Blue"}),
+		NewToken("4", map[string]string{"Eyes": "Laser"}),
+	}
+}
+
+func TestRankMethods(t *testing.T) {
+	tokens := sampleTokens()
+	d := FromTokens(tokens)
+	assert.Equal(t, int64(4), d.Total)
+	assert.Equal(t, int64(2), d.Counts["hat"]["cap"])
+
+	for _, m := range []Method{StatisticalRarity, TraitRaritySum, InformationContent} {
+		scores, err := Rank(d, tokens, Options{Method: m, Missing: true})
+		assert.Nil(t, err, m.String())
+		assert.Len(t, scores, 4)
+		// 1 and 2 are identical and the most common
+		assert.Equal(t, "1", scores[2].TokenID, m.String())
+		assert.Equal(t, "2", scores[3].TokenID, m.String())
+		assert.Equal(t, 3, scores[2].Rank, m.String())
+		assert.Equal(t, 3, scores[3].Rank, m.String())
+	}
+}
+
+func TestRankTraitRaritySum(t *testing.T) {
+	tokens := sampleTokens()
+	scores, err := Rank(FromTokens(tokens), tokens, Options{Method: TraitRaritySum})
+	assert.Nil(t, err)
+
+	byID := map[string]Score{}
+	for _, s := range scores {
+		byID[s.TokenID] = s
+	}
+	// crown 1/4, blue 3/4
+	assert.InDelta(t, 4+4.0/3, byID["3"].Score, 1e-9)
+	// laser 1/4, no hat counted without Missing
+	assert.InDelta(t, 4, byID["4"].Score, 1e-9)
+	assert.Equal(t, 1, byID["3"].Rank)
+
+	scores, err = Rank(FromTokens(tokens), tokens, Options{Method: TraitRaritySum, Missing: true})
+	assert.Nil(t, err)
+	for _, s := range scores {
+		if s.TokenID == "4" {
+			// laser 1/4 plus a missing hat 1/4
+			assert.InDelta(t, 8, s.Score, 1e-9)
+			assert.Equal(t, 1, s.Rank)
+		}
+	}
+}
+
+func TestRankTraitCount(t *testing.T) {
+	tokens := sampleTokens()
+	d := FromTokens(tokens)
+	without, err := Rank(d, tokens, Options{Method: InformationContent})
+	assert.Nil(t, err)
+	with, err := Rank(d, tokens, Options{Method: InformationContent, TraitCount: true})
+	assert.Nil(t, err)
+
+	score := func(scores []Score, id string) float64 {
+		for _, s := range scores {
+			if s.TokenID == id {
+				return s.Score
+			}
+		}
+		return math.NaN()
+	}
+	// token 4 is the only one with a single trait
+	assert.Greater(t, score(with, "4")/score(with, "3"), score(without, "4")/score(without, "3"))
+}
+
+func TestRankEmptyDistribution(t *testing.T) {
+	_, err := Rank(&Distribution{}, sampleTokens(), Options{})
+	assert.NotNil(t, err)
+}
+
+func TestFromCollection(t *testing.T) {
+	b, err := ioutil.ReadFile("../test-files/opensea-collection-doodles.json")
+	assert.Nil(t, err)
+	var resp opensea.CollectionSingleResponse
+	assert.Nil(t, json.Unmarshal(b, &resp))
+
+	d := FromCollection(resp.Collection)
+	assert.Equal(t, int64(9999), d.Total)
+	assert.Equal(t, int64(1), d.Counts["head"]["purple alien"])
+
+	tokens := []Token{
+		NewToken("rare", map[string]string{"Head": "Purple Alien", "Background": "Deeper Space"}),
+		NewToken("common", map[string]string{"Head": "Tan", "Background": "Light Blue"}),
+	}
+	scores, err := Rank(d, tokens, Options{Method: StatisticalRarity, Missing: true})
+	assert.Nil(t, err)
+	assert.Equal(t, "rare", scores[0].TokenID)
+	assert.Equal(t, 1, scores[0].Rank)
+	assert.Equal(t, 2, scores[1].Rank)
+}
+
+func TestFromAssets(t *testing.T) {
+	b, err := ioutil.ReadFile("../test-files/opensea-assets-collectibles.json")
+	assert.Nil(t, err)
+	var resp opensea.AssetResponse
+	assert.Nil(t, json.Unmarshal(b, &resp))
+
+	d := FromAssets(resp.Assets)
+	assert.Equal(t, int64(len(resp.Assets)), d.Total)
+	// numeric values are kept as text
+	assert.Equal(t, int64(1), d.Counts["serial number"]["2854"])
+}
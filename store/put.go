@@ -0,0 +1,184 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/jumpblock/go-opensea"
+	"github.com/jumpblock/go-opensea/export"
+)
+
+var (
+	eventColumns = []string{"event_type", "timestamp", "collection_slug", "contract_address", "token_id",
+		"quantity", "seller", "buyer", "from_address", "to_address", "price", "price_decimals", "price_eth",
+		"price_usd", "payment_token_symbol", "payment_token_address", "transaction_hash", "block_number", "data"}
+	orderColumns = []string{"protocol", "chain", "side", "order_type", "created_date", "listing_time",
+		"expiration_time", "maker", "taker", "collection_slug", "contract_address", "token_id", "quantity",
+		"price", "price_decimals", "price_eth", "payment_token_symbol", "payment_token_address", "cancelled",
+		"finalized", "marked_invalid", "data", "updated_at"}
+)
+
+// put upserts n rows in one transaction; row returns the values of the keys then the columns of row i.
+func (s *Store) put(ctx context.Context, table string, keys, columns []string, n int, row func(i int) ([]interface{}, error)) error {
+	if n == 0 {
+		return nil
+	}
+	return s.tx(ctx, func(tx *sql.Tx) error {
+		return putTx(ctx, tx, table, keys, columns, n, row)
+	})
+}
+
+// putTx is put within tx.
+func putTx(ctx context.Context, tx *sql.Tx, table string, keys, columns []string, n int, row func(i int) ([]interface{}, error)) error {
+	if n == 0 {
+		return nil
+	}
+	stmt, err := upsert(ctx, tx, table, keys, columns)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	for i := 0; i < n; i++ {
+		values, err := row(i)
+		if err == nil {
+			_, err = stmt.ExecContext(ctx, values...)
+		}
+		if err != nil {
+			return fmt.Errorf("store: %s %d: %w", table, i, err)
+		}
+	}
+	return nil
+}
+
+func (s *Store) PutCollections(ctx context.Context, collections ...*opensea.Collection) error {
+	return s.put(ctx, "collections", []string{"slug"}, []string{"name", "created_date", "data", "updated_at"},
+		len(collections), func(i int) ([]interface{}, error) {
+			c := collections[i]
+			data, err := marshal(c)
+			return []interface{}{c.Slug, c.Name, c.CreatedDate, data, now()}, err
+		})
+}
+
+func (s *Store) PutContracts(ctx context.Context, contracts ...*opensea.Contract) error {
+	return s.put(ctx, "contracts", []string{"address"}, []string{"name", "symbol", "schema_name", "collection_slug", "data", "updated_at"},
+		len(contracts), func(i int) ([]interface{}, error) {
+			c := contracts[i]
+			data, err := marshal(c)
+			return []interface{}{c.Address.String(), c.Name, c.Symbol, c.SchemaName, c.Collection.Slug, data, now()}, err
+		})
+}
+
+// PutAssets stores assets by contract and token ID; assets without a contract are rejected.
+func (s *Store) PutAssets(ctx context.Context, assets ...*opensea.Asset) error {
+	if len(assets) == 0 {
+		return nil
+	}
+	return s.tx(ctx, func(tx *sql.Tx) error {
+		return putAssets(ctx, tx, assets)
+	})
+}
+
+func putAssets(ctx context.Context, tx *sql.Tx, assets []*opensea.Asset) error {
+	return putTx(ctx, tx, "assets", []string{"contract_address", "token_id"}, []string{"name", "collection_slug", "owner", "num_sales", "data", "updated_at"},
+		len(assets), func(i int) ([]interface{}, error) {
+			a := assets[i]
+			if a.AssetContract == nil {
+				return nil, fmt.Errorf("asset %s has no contract", a.TokenID)
+			}
+			var slug, owner string
+			if a.Collection != nil {
+				slug = a.Collection.Slug
+			}
+			if a.Owner != nil {
+				owner = a.Owner.Address.String()
+			}
+			data, err := marshal(a)
+			return []interface{}{a.AssetContract.Address.String(), a.TokenID, a.Name, slug, owner, a.NumSales, data, now()}, err
+		})
+}
+
+// PutEvents stores events by ID, with the columns of export.EventRecord.
+func (s *Store) PutEvents(ctx context.Context, events ...*opensea.Event) error {
+	return s.put(ctx, "events", []string{"id"}, eventColumns, len(events), func(i int) ([]interface{}, error) {
+		r := export.NewEventRecord(events[i])
+		data, err := marshal(events[i])
+		return []interface{}{r.ID, r.EventType, r.Timestamp, r.CollectionSlug, r.ContractAddress, r.TokenID,
+			r.Quantity, r.Seller, r.Buyer, r.FromAddress, r.ToAddress, r.Price, r.PriceDecimals, r.PriceEth,
+			r.PriceUsd, r.PaymentTokenSymbol, r.PaymentTokenAddress, r.TransactionHash, r.BlockNumber, data}, err
+	})
+}
+
+// PutOrders stores v1 orders by hash; chain resolves their payment tokens.
+func (s *Store) PutOrders(ctx context.Context, chain opensea.Chain, orders ...*opensea.Order) error {
+	return s.put(ctx, "orders", []string{"order_hash"}, orderColumns, len(orders), func(i int) ([]interface{}, error) {
+		return orderRow(chain, export.NewOrderRecord(chain, orders[i]), orders[i])
+	})
+}
+
+// PutOrdersV2 stores Seaport orders by hash; chain resolves their payment tokens.
+func (s *Store) PutOrdersV2(ctx context.Context, chain opensea.Chain, orders ...*opensea.OrderV2) error {
+	if len(orders) == 0 {
+		return nil
+	}
+	return s.tx(ctx, func(tx *sql.Tx) error {
+		return putOrdersV2(ctx, tx, chain, orders)
+	})
+}
+
+func putOrdersV2(ctx context.Context, tx *sql.Tx, chain opensea.Chain, orders []*opensea.OrderV2) error {
+	return putTx(ctx, tx, "orders", []string{"order_hash"}, orderColumns, len(orders), func(i int) ([]interface{}, error) {
+		return orderRow(chain, export.NewOrderRecordV2(chain, orders[i]), orders[i])
+	})
+}
+
+func orderRow(chain opensea.Chain, r export.OrderRecord, o interface{}) ([]interface{}, error) {
+	data, err := marshal(o)
+	return []interface{}{r.OrderHash, r.Protocol, string(chain), r.Side, r.OrderType, r.CreatedDate, r.ListingTime,
+		r.ExpirationTime, r.Maker, r.Taker, r.CollectionSlug, r.ContractAddress, r.TokenID, r.Quantity,
+		r.Price, r.PriceDecimals, r.PriceEth, r.PaymentTokenSymbol, r.PaymentTokenAddress, r.Cancelled,
+		r.Finalized, r.MarkedInvalid, data, now()}, err
+}
+
+// Sink stores the tokens of a crawl: their assets, and their listings on chain. The Seaport listings
+// stored for a token that are missing from its snapshot, having been filled or cancelled since, are
+// deleted, unless the crawl skipped listings.
+func (s *Store) Sink(chain opensea.Chain) opensea.CrawlSink {
+	return opensea.CrawlSinkFunc(func(ctx context.Context, tokens []*opensea.TokenSnapshot) error {
+		assets := make([]*opensea.Asset, len(tokens))
+		var listings []*opensea.OrderV2
+		for i, t := range tokens {
+			assets[i] = t.Asset
+			listings = append(listings, t.Listings...)
+		}
+		return s.tx(ctx, func(tx *sql.Tx) error {
+			if err := putAssets(ctx, tx, assets); err != nil {
+				return err
+			}
+			for _, t := range tokens {
+				if err := deleteStaleListings(ctx, tx, chain, t); err != nil {
+					return err
+				}
+			}
+			return putOrdersV2(ctx, tx, chain, listings)
+		})
+	})
+}
+
+// deleteStaleListings deletes the Seaport asks stored for the token of t that are not among its listings.
+func deleteStaleListings(ctx context.Context, tx *sql.Tx, chain opensea.Chain, t *opensea.TokenSnapshot) error {
+	if t.Listings == nil || t.Asset.AssetContract == nil {
+		return nil
+	}
+	q := "DELETE FROM orders WHERE protocol = 'seaport' AND side = 'ask' AND chain = ? AND contract_address = ? AND token_id = ?"
+	args := []interface{}{string(chain), strings.ToLower(t.Asset.AssetContract.Address.String()), t.Asset.TokenID}
+	if len(t.Listings) > 0 {
+		q += " AND order_hash NOT IN (?" + strings.Repeat(", ?", len(t.Listings)-1) + ")"
+		for _, o := range t.Listings {
+			args = append(args, o.OrderHash)
+		}
+	}
+	_, err := tx.ExecContext(ctx, q, args...)
+	return err
+}
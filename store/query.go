@@ -0,0 +1,143 @@
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/jumpblock/go-opensea"
+)
+
+func (s *Store) GetCollection(ctx context.Context, slug string) (*opensea.Collection, error) {
+	c := new(opensea.Collection)
+	if err := s.get(ctx, c, "SELECT data FROM collections WHERE slug = ?", slug); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (s *Store) GetContract(ctx context.Context, address string) (*opensea.Contract, error) {
+	c := new(opensea.Contract)
+	if err := s.get(ctx, c, "SELECT data FROM contracts WHERE address = ?", strings.ToLower(address)); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+func (s *Store) GetAsset(ctx context.Context, contract, tokenID string) (*opensea.Asset, error) {
+	a := new(opensea.Asset)
+	if err := s.get(ctx, a, "SELECT data FROM assets WHERE contract_address = ? AND token_id = ?", strings.ToLower(contract), tokenID); err != nil {
+		return nil, err
+	}
+	return a, nil
+}
+
+// CollectionAssets returns the assets of a collection in token ID order.
+func (s *Store) CollectionAssets(ctx context.Context, slug string) ([]*opensea.Asset, error) {
+	var ret []*opensea.Asset
+	err := s.query(ctx, func() interface{} {
+		ret = append(ret, new(opensea.Asset))
+		return ret[len(ret)-1]
+	}, "SELECT data FROM assets WHERE collection_slug = ? ORDER BY contract_address, length(token_id), token_id", slug)
+	return ret, err
+}
+
+// Sales returns the successful events of a collection in [from, to), oldest first. A zero from or to
+// leaves that end open.
+func (s *Store) Sales(ctx context.Context, slug string, from, to time.Time) ([]*opensea.Event, error) {
+	q := "SELECT data FROM events WHERE collection_slug = ? AND event_type = ?"
+	args := []interface{}{slug, string(opensea.EventTypeSuccessful)}
+	if !from.IsZero() {
+		q += " AND timestamp >= ?"
+		args = append(args, millis(from))
+	}
+	if !to.IsZero() {
+		q += " AND timestamp < ?"
+		args = append(args, millis(to))
+	}
+	var ret []*opensea.Event
+	err := s.query(ctx, func() interface{} {
+		ret = append(ret, new(opensea.Event))
+		return ret[len(ret)-1]
+	}, q+" ORDER BY timestamp, id", args...)
+	return ret, err
+}
+
+// LastSale returns the latest successful event of a token.
+func (s *Store) LastSale(ctx context.Context, contract, tokenID string) (*opensea.Event, error) {
+	e := new(opensea.Event)
+	if err := s.get(ctx, e, "SELECT data FROM events WHERE contract_address = ? AND token_id = ? AND event_type = ? ORDER BY timestamp DESC, id DESC LIMIT 1",
+		strings.ToLower(contract), tokenID, string(opensea.EventTypeSuccessful)); err != nil {
+		return nil, err
+	}
+	return e, nil
+}
+
+// listings selects the asks on a token that are live at a time: listed, not yet expired, and neither
+// cancelled, filled nor invalid. The cheapest in ETH come first.
+const listings = `SELECT data FROM orders
+	WHERE contract_address = ? AND token_id = ? AND side = 'ask' AND protocol = ?
+	AND NOT cancelled AND NOT finalized AND NOT marked_invalid
+	AND (listing_time IS NULL OR listing_time <= ?) AND (expiration_time IS NULL OR expiration_time > ?)
+	ORDER BY price_eth IS NULL, price_eth, listing_time`
+
+// Listings returns the v1 listings of a token live at time at, now if zero.
+func (s *Store) Listings(ctx context.Context, contract, tokenID string, at time.Time) ([]*opensea.Order, error) {
+	if at.IsZero() {
+		at = time.Now()
+	}
+	var ret []*opensea.Order
+	err := s.query(ctx, func() interface{} {
+		ret = append(ret, new(opensea.Order))
+		return ret[len(ret)-1]
+	}, listings, strings.ToLower(contract), tokenID, "v1", millis(at), millis(at))
+	return ret, err
+}
+
+// ListingsV2 returns the Seaport listings of a token live at time at, now if zero.
+func (s *Store) ListingsV2(ctx context.Context, contract, tokenID string, at time.Time) ([]*opensea.OrderV2, error) {
+	if at.IsZero() {
+		at = time.Now()
+	}
+	var ret []*opensea.OrderV2
+	err := s.query(ctx, func() interface{} {
+		ret = append(ret, new(opensea.OrderV2))
+		return ret[len(ret)-1]
+	}, listings, strings.ToLower(contract), tokenID, "seaport", millis(at), millis(at))
+	return ret, err
+}
+
+// get decodes the data of the one row of q into v.
+func (s *Store) get(ctx context.Context, v interface{}, q string, args ...interface{}) error {
+	var data string
+	err := s.db.QueryRowContext(ctx, q, args...).Scan(&data)
+	if errors.Is(err, sql.ErrNoRows) {
+		return ErrNotFound
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal([]byte(data), v)
+}
+
+// query decodes the data of every row of q into a value from next.
+func (s *Store) query(ctx context.Context, next func() interface{}, q string, args ...interface{}) error {
+	rows, err := s.db.QueryContext(ctx, q, args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var data string
+		if err := rows.Scan(&data); err != nil {
+			return err
+		}
+		if err := json.Unmarshal([]byte(data), next()); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
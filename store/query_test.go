@@ -0,0 +1,114 @@
+package store
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jumpblock/go-opensea"
+	"github.com/stretchr/testify/assert"
+)
+
+const mafriends = "0x495f947276749ce646f68ac8c248420045cb7b5e"
+
+func TestSales(t *testing.T) {
+	ctx := context.Background()
+	s := openTest(t)
+	var resp opensea.AssetEventsResponse
+	readFixture(t, "opensea-events.json", &resp)
+	assert.Nil(t, s.PutEvents(ctx, resp.AssetEvents...))
+	assert.Nil(t, s.PutEvents(ctx, &opensea.Event{ID: 1, EventType: opensea.EventTypeTransfer, CollectionSlug: "forgotten-runes-mafriends"}))
+
+	all, err := s.Sales(ctx, "forgotten-runes-mafriends", time.Time{}, time.Time{})
+	assert.Nil(t, err)
+	assert.Len(t, all, 8)
+	assert.Equal(t, uint64(651665248), all[0].ID)
+	assert.Equal(t, uint64(1814274536), all[7].ID)
+	assert.Equal(t, "0.2", all[0].TotalPrice.Units())
+
+	september, err := s.Sales(ctx, "forgotten-runes-mafriends",
+		time.Date(2021, 9, 1, 0, 0, 0, 0, time.UTC), time.Date(2021, 10, 1, 0, 0, 0, 0, time.UTC))
+	assert.Nil(t, err)
+	var ids []uint64
+	for _, e := range september {
+		ids = append(ids, e.ID)
+	}
+	assert.Equal(t, []uint64{844762932, 848189305, 1003428491, 1029241725}, ids)
+
+	last, err := s.LastSale(ctx, mafriends, "85056077992304551101375793045702088550737915351281032496559888517320457322497")
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(1705791568), last.ID)
+	_, err = s.LastSale(ctx, mafriends, "1")
+	assert.Equal(t, ErrNotFound, err)
+}
+
+func TestCollectionAssets(t *testing.T) {
+	ctx := context.Background()
+	s := openTest(t)
+	contract := &opensea.AssetContract{Address: "0x251b5f14a825c537ff788604ea1b58e49b70726f"}
+	collection := &opensea.Collection{Slug: "forgottensouls"}
+	for _, id := range []string{"6044", "10", "6036"} {
+		assert.Nil(t, s.PutAssets(ctx, &opensea.Asset{TokenID: id, AssetContract: contract, Collection: collection}))
+	}
+	assets, err := s.CollectionAssets(ctx, "forgottensouls")
+	assert.Nil(t, err)
+	var ids []string
+	for _, a := range assets {
+		ids = append(ids, a.TokenID)
+	}
+	assert.Equal(t, []string{"10", "6036", "6044"}, ids)
+}
+
+func TestListingsV2(t *testing.T) {
+	ctx := context.Background()
+	s := openTest(t)
+	var listing opensea.OrderV2
+	readFixture(t, "listings-v2.json", &listing)
+	listing.MarkedInvalid = false
+	token := listing.MakerAssetBundle.Assets[0]
+	contract := token.AssetContract.Address.String()
+	during := listing.ListingTime.Time().Add(time.Hour)
+
+	cheaper := listing
+	cheaper.OrderHash = "0x02"
+	cheaper.CurrentPrice = opensea.Ether("0.5")
+	cheaper.ProtocolData = nil
+	cancelled := listing
+	cancelled.OrderHash, cancelled.Cancelled = "0x03", true
+	bid := listing
+	bid.OrderHash, bid.Side = "0x04", "bid"
+	invalid := listing
+	invalid.OrderHash, invalid.MarkedInvalid = "0x05", true
+	assert.Nil(t, s.PutOrdersV2(ctx, opensea.ChainEthereum, &listing, &cheaper, &cancelled, &bid, &invalid))
+
+	live, err := s.ListingsV2(ctx, contract, token.TokenID, during)
+	assert.Nil(t, err)
+	if assert.Len(t, live, 2) {
+		assert.Equal(t, "0x02", live[0].OrderHash)
+		assert.Equal(t, listing.OrderHash, live[1].OrderHash)
+		assert.Equal(t, listing.CurrentPrice, live[1].CurrentPrice)
+	}
+
+	live, err = s.ListingsV2(ctx, contract, token.TokenID, listing.ExpirationTime.Time())
+	assert.Nil(t, err)
+	assert.Empty(t, live)
+	live, err = s.ListingsV2(ctx, contract, token.TokenID, listing.ListingTime.Time().Add(-time.Second))
+	assert.Nil(t, err)
+	assert.Empty(t, live)
+
+	// Filling a listing updates it in place.
+	listing.Finalized = true
+	assert.Nil(t, s.PutOrdersV2(ctx, opensea.ChainEthereum, &listing))
+	live, err = s.ListingsV2(ctx, contract, token.TokenID, during)
+	assert.Nil(t, err)
+	assert.Len(t, live, 1)
+
+	// v1 orders are kept apart.
+	v1 := &opensea.Order{OrderHash: "0x06", Side: opensea.Sell, CurrentPrice: opensea.Ether("1"), Asset: *token}
+	assert.Nil(t, s.PutOrders(ctx, opensea.ChainEthereum, v1))
+	orders, err := s.Listings(ctx, contract, token.TokenID, time.Time{})
+	assert.Nil(t, err)
+	if assert.Len(t, orders, 1) {
+		assert.Equal(t, "0x06", orders[0].OrderHash)
+	}
+}
@@ -0,0 +1,214 @@
+// Package store keeps assets, collections, contracts, events and orders in SQLite, through the pure
+// Go driver modernc.org/sqlite, so that crawlers and dashboards can share state without a server.
+//
+// Every table is keyed by the natural ID of its rows, and the Put methods upsert: storing an object
+// again replaces the stored copy. Rows keep the flattened columns of the export package, for queries
+// in SQL, next to the object as JSON, which the query helpers decode.
+package store
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// ErrNotFound is returned by the Get methods when nothing is stored under the key.
+var ErrNotFound = errors.New("store: not found")
+
+// migrations are applied in order; PRAGMA user_version records how many ran. Append, never edit.
+var migrations = []string{
+	`CREATE TABLE collections (
+		slug         TEXT PRIMARY KEY,
+		name         TEXT NOT NULL,
+		created_date TEXT NOT NULL,
+		data         TEXT NOT NULL,
+		updated_at   INTEGER NOT NULL
+	);
+	CREATE TABLE contracts (
+		address         TEXT PRIMARY KEY,
+		name            TEXT NOT NULL,
+		symbol          TEXT NOT NULL,
+		schema_name     TEXT NOT NULL,
+		collection_slug TEXT NOT NULL,
+		data            TEXT NOT NULL,
+		updated_at      INTEGER NOT NULL
+	);
+	CREATE TABLE assets (
+		contract_address TEXT NOT NULL,
+		token_id         TEXT NOT NULL,
+		name             TEXT NOT NULL,
+		collection_slug  TEXT NOT NULL,
+		owner            TEXT NOT NULL,
+		num_sales        INTEGER NOT NULL,
+		data             TEXT NOT NULL,
+		updated_at       INTEGER NOT NULL,
+		PRIMARY KEY (contract_address, token_id)
+	);
+	CREATE INDEX assets_collection ON assets (collection_slug);
+	CREATE TABLE events (
+		id                    INTEGER PRIMARY KEY,
+		event_type            TEXT NOT NULL,
+		timestamp             INTEGER,
+		collection_slug       TEXT NOT NULL,
+		contract_address      TEXT NOT NULL,
+		token_id              TEXT NOT NULL,
+		quantity              TEXT NOT NULL,
+		seller                TEXT NOT NULL,
+		buyer                 TEXT NOT NULL,
+		from_address          TEXT NOT NULL,
+		to_address            TEXT NOT NULL,
+		price                 TEXT,
+		price_decimals        INTEGER NOT NULL,
+		price_eth             REAL,
+		price_usd             REAL,
+		payment_token_symbol  TEXT NOT NULL,
+		payment_token_address TEXT NOT NULL,
+		transaction_hash      TEXT NOT NULL,
+		block_number          INTEGER,
+		data                  TEXT NOT NULL
+	);
+	CREATE INDEX events_collection ON events (collection_slug, event_type, timestamp);
+	CREATE INDEX events_token ON events (contract_address, token_id, timestamp);
+	CREATE TABLE orders (
+		order_hash            TEXT PRIMARY KEY,
+		protocol              TEXT NOT NULL,
+		chain                 TEXT NOT NULL,
+		side                  TEXT NOT NULL,
+		order_type            TEXT NOT NULL,
+		created_date          INTEGER,
+		listing_time          INTEGER,
+		expiration_time       INTEGER,
+		maker                 TEXT NOT NULL,
+		taker                 TEXT NOT NULL,
+		collection_slug       TEXT NOT NULL,
+		contract_address      TEXT NOT NULL,
+		token_id              TEXT NOT NULL,
+		quantity              TEXT NOT NULL,
+		price                 TEXT,
+		price_decimals        INTEGER NOT NULL,
+		price_eth             REAL,
+		payment_token_symbol  TEXT NOT NULL,
+		payment_token_address TEXT NOT NULL,
+		cancelled             INTEGER NOT NULL,
+		finalized             INTEGER NOT NULL,
+		marked_invalid        INTEGER NOT NULL,
+		data                  TEXT NOT NULL,
+		updated_at            INTEGER NOT NULL
+	);
+	CREATE INDEX orders_token ON orders (contract_address, token_id, side);`,
+}
+
+type Store struct {
+	db *sql.DB
+}
+
+// Open opens, or creates, the SQLite database at path and migrates it. ":memory:" opens a private
+// database in memory.
+func Open(ctx context.Context, path string) (*Store, error) {
+	dsn := "file:" + path + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(10000)"
+	if path != ":memory:" {
+		dsn += "&_pragma=journal_mode(WAL)"
+	}
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, err
+	}
+	// SQLite takes one writer at a time, and every connection to ":memory:" is a new database.
+	db.SetMaxOpenConns(1)
+	s, err := New(ctx, db)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// New migrates the SQLite database db and stores into it.
+func New(ctx context.Context, db *sql.DB) (*Store, error) {
+	s := &Store{db: db}
+	if err := s.migrate(ctx); err != nil {
+		return nil, fmt.Errorf("store: migrate: %w", err)
+	}
+	return s, nil
+}
+
+// DB is the database of s, for queries the helpers do not cover.
+func (s *Store) DB() *sql.DB {
+	return s.db
+}
+
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+func (s *Store) migrate(ctx context.Context) error {
+	var version int
+	if err := s.db.QueryRowContext(ctx, "PRAGMA user_version").Scan(&version); err != nil {
+		return err
+	}
+	if version > len(migrations) {
+		return fmt.Errorf("schema version %d is newer than this package (%d)", version, len(migrations))
+	}
+	for i := version; i < len(migrations); i++ {
+		err := s.tx(ctx, func(tx *sql.Tx) error {
+			if _, err := tx.ExecContext(ctx, migrations[i]); err != nil {
+				return err
+			}
+			_, err := tx.ExecContext(ctx, fmt.Sprintf("PRAGMA user_version = %d", i+1))
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("version %d: %w", i+1, err)
+		}
+	}
+	return nil
+}
+
+// tx runs fn in a transaction, committed if fn succeeds.
+func (s *Store) tx(ctx context.Context, fn func(*sql.Tx) error) error {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	if err := fn(tx); err != nil {
+		tx.Rollback()
+		return err
+	}
+	return tx.Commit()
+}
+
+// upsert prepares the statement inserting columns into table, or updating the row with the same keys.
+func upsert(ctx context.Context, tx *sql.Tx, table string, keys []string, columns []string) (*sql.Stmt, error) {
+	all := append(append([]string{}, keys...), columns...)
+	set := make([]string, len(columns))
+	for i, c := range columns {
+		set[i] = c + " = excluded." + c
+	}
+	q := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) ON CONFLICT (%s) DO UPDATE SET %s",
+		table, strings.Join(all, ", "), strings.TrimSuffix(strings.Repeat("?, ", len(all)), ", "),
+		strings.Join(keys, ", "), strings.Join(set, ", "))
+	return tx.PrepareContext(ctx, q)
+}
+
+func marshal(v interface{}) (string, error) {
+	b, err := json.Marshal(v)
+	return string(b), err
+}
+
+// millis is the INTEGER value of timestamps, null for the zero time.
+func millis(t time.Time) interface{} {
+	if t.IsZero() {
+		return nil
+	}
+	return t.UnixNano() / int64(time.Millisecond)
+}
+
+func now() int64 {
+	return time.Now().UnixNano() / int64(time.Millisecond)
+}
@@ -0,0 +1,127 @@
+package store
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/jumpblock/go-opensea"
+	"github.com/stretchr/testify/assert"
+)
+
+func readFixture(t *testing.T, name string, v interface{}) {
+	b, err := ioutil.ReadFile("../test-files/" + name)
+	assert.Nil(t, err)
+	assert.Nil(t, json.Unmarshal(b, v))
+}
+
+func openTest(t *testing.T) *Store {
+	s, err := Open(context.Background(), ":memory:")
+	assert.Nil(t, err)
+	t.Cleanup(func() { s.Close() })
+	return s
+}
+
+func TestMigrate(t *testing.T) {
+	ctx := context.Background()
+	path := filepath.Join(t.TempDir(), "opensea.db")
+	s, err := Open(ctx, path)
+	assert.Nil(t, err)
+	assert.Nil(t, s.PutCollections(ctx, &opensea.Collection{Slug: "doodles-official", Name: "Doodles"}))
+	assert.Nil(t, s.Close())
+
+	// Reopening keeps the data and runs no migration twice.
+	s, err = Open(ctx, path)
+	assert.Nil(t, err)
+	defer s.Close()
+	var version int
+	assert.Nil(t, s.DB().QueryRow("PRAGMA user_version").Scan(&version))
+	assert.Equal(t, len(migrations), version)
+	c, err := s.GetCollection(ctx, "doodles-official")
+	assert.Nil(t, err)
+	assert.Equal(t, "Doodles", c.Name)
+
+	_, err = s.DB().Exec("PRAGMA user_version = 99")
+	assert.Nil(t, err)
+	_, err = New(ctx, s.DB())
+	assert.EqualError(t, err, "store: migrate: schema version 99 is newer than this package (1)")
+}
+
+func TestPutUpserts(t *testing.T) {
+	ctx := context.Background()
+	s := openTest(t)
+
+	var contract opensea.Contract
+	readFixture(t, "opeansea-contract.json", &contract)
+	assert.Nil(t, s.PutContracts(ctx, &contract))
+	contract.Name = "Renamed"
+	assert.Nil(t, s.PutContracts(ctx, &contract))
+	got, err := s.GetContract(ctx, "0xDCEAF1652A131F32A821468DC03A92DF0EDD86EA")
+	assert.Nil(t, err)
+	assert.Equal(t, "Renamed", got.Name)
+	assert.Equal(t, "mycryptoheroes", got.Collection.Slug)
+
+	var assets struct{ Assets []*opensea.Asset }
+	readFixture(t, "opensea-assets-collectibles.json", &assets)
+	assert.Nil(t, s.PutAssets(ctx, assets.Assets...))
+	assert.Nil(t, s.PutAssets(ctx, assets.Assets...))
+	var n int
+	assert.Nil(t, s.DB().QueryRow("SELECT count(*) FROM assets").Scan(&n))
+	assert.Equal(t, len(assets.Assets), n)
+
+	a, err := s.GetAsset(ctx, "0x251b5f14a825c537ff788604ea1b58e49b70726f", "6044")
+	assert.Nil(t, err)
+	assert.Equal(t, assets.Assets[1], a)
+
+	_, err = s.GetAsset(ctx, "0x251b5f14a825c537ff788604ea1b58e49b70726f", "1")
+	assert.Equal(t, ErrNotFound, err)
+
+	// A bad row rolls back the whole batch.
+	err = s.PutAssets(ctx, &opensea.Asset{TokenID: "1", AssetContract: &opensea.AssetContract{Address: "0x01"}}, &opensea.Asset{TokenID: "2"})
+	assert.EqualError(t, err, "store: assets 1: asset 2 has no contract")
+	_, err = s.GetAsset(ctx, "0x01", "1")
+	assert.Equal(t, ErrNotFound, err)
+}
+
+func TestSink(t *testing.T) {
+	ctx := context.Background()
+	s := openTest(t)
+	var listing opensea.OrderV2
+	readFixture(t, "listings-v2.json", &listing)
+	listing.MarkedInvalid = false
+	asset := listing.MakerAssetBundle.Assets[0]
+	asset.LastSale = &opensea.Event{EventType: opensea.EventTypeSuccessful, TotalPrice: opensea.Ether("0.5")}
+
+	sink := s.Sink(opensea.ChainEthereum)
+	assert.Nil(t, sink.WriteTokens(ctx, []*opensea.TokenSnapshot{{Asset: asset, Listings: []*opensea.OrderV2{&listing}}}))
+
+	a, err := s.GetAsset(ctx, asset.AssetContract.Address.String(), asset.TokenID)
+	assert.Nil(t, err)
+	assert.Equal(t, "0.5", a.LastSale.TotalPrice.Units())
+	contract, at := asset.AssetContract.Address.String(), listing.ListingTime.Time()
+	live, err := s.ListingsV2(ctx, contract, asset.TokenID, at)
+	assert.Nil(t, err)
+	assert.Len(t, live, 1)
+
+	// The next crawl finds another listing: the first one was filled or cancelled meanwhile.
+	relisted := listing
+	relisted.OrderHash = "0x02"
+	assert.Nil(t, sink.WriteTokens(ctx, []*opensea.TokenSnapshot{{Asset: asset, Listings: []*opensea.OrderV2{&relisted}}}))
+	live, err = s.ListingsV2(ctx, contract, asset.TokenID, at)
+	assert.Nil(t, err)
+	if assert.Len(t, live, 1) {
+		assert.Equal(t, "0x02", live[0].OrderHash)
+	}
+
+	// A crawl skipping listings keeps them; one finding none drops them.
+	assert.Nil(t, sink.WriteTokens(ctx, []*opensea.TokenSnapshot{{Asset: asset}}))
+	live, err = s.ListingsV2(ctx, contract, asset.TokenID, at)
+	assert.Nil(t, err)
+	assert.Len(t, live, 1)
+	assert.Nil(t, sink.WriteTokens(ctx, []*opensea.TokenSnapshot{{Asset: asset, Listings: []*opensea.OrderV2{}}}))
+	live, err = s.ListingsV2(ctx, contract, asset.TokenID, at)
+	assert.Nil(t, err)
+	assert.Empty(t, live)
+}
@@ -0,0 +1,196 @@
+package stream
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/jumpblock/go-opensea"
+)
+
+// AllEventTypes are the event types OpenSea sends on a collection topic.
+var AllEventTypes = []opensea.EventType{
+	opensea.EventTypeItemListed,
+	opensea.EventTypeItemSold,
+	opensea.EventTypeItemTransferred,
+	opensea.EventTypeItemMetadataUpdated,
+	opensea.EventTypeItemCancelled,
+	opensea.EventTypeItemReceivedOffer,
+	opensea.EventTypeItemReceivedBid,
+	opensea.EventTypeCollectionOffer,
+	opensea.EventTypeTraitOffer,
+}
+
+// Event is one event received on a collection topic. Payload is a pointer to the struct of its type,
+// such as *ItemListed, or nil for a type this package does not know, whose Raw payload is still kept.
+type Event struct {
+	Type       opensea.EventType
+	Collection string
+	SentAt     opensea.TimeNano
+	Payload    interface{}
+	Raw        json.RawMessage
+}
+
+type Account struct {
+	Address opensea.Address `json:"address"`
+}
+
+type Collection struct {
+	Slug string `json:"slug"`
+}
+
+type Chain struct {
+	Name string `json:"name"`
+}
+
+type ItemMetadata struct {
+	Name         string          `json:"name"`
+	Description  string          `json:"description"`
+	ImageURL     string          `json:"image_url"`
+	AnimationURL string          `json:"animation_url"`
+	MetadataURL  string          `json:"metadata_url"`
+	Traits       []opensea.Trait `json:"traits"`
+}
+
+// Item is the NFT an event is about; NftID reads chain/contract/token_id.
+type Item struct {
+	NftID     string       `json:"nft_id"`
+	Permalink string       `json:"permalink"`
+	Metadata  ItemMetadata `json:"metadata"`
+	Chain     Chain        `json:"chain"`
+}
+
+type PaymentToken struct {
+	Address  opensea.Address `json:"address"`
+	Decimals int             `json:"decimals"`
+	EthPrice opensea.Decimal `json:"eth_price"`
+	Name     string          `json:"name"`
+	Symbol   string          `json:"symbol"`
+	UsdPrice opensea.Decimal `json:"usd_price"`
+}
+
+type Transaction struct {
+	Hash      string           `json:"hash"`
+	Timestamp opensea.TimeNano `json:"timestamp"`
+}
+
+type ItemListed struct {
+	Item           Item             `json:"item"`
+	Collection     Collection       `json:"collection"`
+	EventTimestamp opensea.TimeNano `json:"event_timestamp"`
+	BasePrice      opensea.Amount   `json:"base_price"`
+	ListingDate    opensea.TimeNano `json:"listing_date"`
+	ExpirationDate opensea.TimeNano `json:"expiration_date"`
+	ListingType    string           `json:"listing_type"`
+	IsPrivate      bool             `json:"is_private"`
+	Maker          Account          `json:"maker"`
+	Taker          *Account         `json:"taker"`
+	PaymentToken   PaymentToken     `json:"payment_token"`
+	Quantity       int64            `json:"quantity"`
+	OrderHash      string           `json:"order_hash"`
+}
+
+type ItemSold struct {
+	Item           Item             `json:"item"`
+	Collection     Collection       `json:"collection"`
+	EventTimestamp opensea.TimeNano `json:"event_timestamp"`
+	ClosingDate    opensea.TimeNano `json:"closing_date"`
+	SalePrice      opensea.Amount   `json:"sale_price"`
+	ListingType    string           `json:"listing_type"`
+	IsPrivate      bool             `json:"is_private"`
+	Maker          Account          `json:"maker"`
+	Taker          Account          `json:"taker"`
+	PaymentToken   PaymentToken     `json:"payment_token"`
+	Quantity       int64            `json:"quantity"`
+	OrderHash      string           `json:"order_hash"`
+	Transaction    Transaction      `json:"transaction"`
+}
+
+type ItemTransferred struct {
+	Item           Item             `json:"item"`
+	Collection     Collection       `json:"collection"`
+	EventTimestamp opensea.TimeNano `json:"event_timestamp"`
+	FromAccount    Account          `json:"from_account"`
+	ToAccount      Account          `json:"to_account"`
+	Quantity       int64            `json:"quantity"`
+	Transaction    Transaction      `json:"transaction"`
+}
+
+type ItemMetadataUpdated struct {
+	Item       Item       `json:"item"`
+	Collection Collection `json:"collection"`
+}
+
+type ItemCancelled struct {
+	Item           Item             `json:"item"`
+	Collection     Collection       `json:"collection"`
+	EventTimestamp opensea.TimeNano `json:"event_timestamp"`
+	ListingType    string           `json:"listing_type"`
+	PaymentToken   PaymentToken     `json:"payment_token"`
+	Quantity       int64            `json:"quantity"`
+	OrderHash      string           `json:"order_hash"`
+	Transaction    Transaction      `json:"transaction"`
+}
+
+// Offer is the payload of item_received_offer, item_received_bid, collection_offer and trait_offer.
+// Item is set for the first two, the criteria for the other two.
+type Offer struct {
+	Item                  *Item            `json:"item"`
+	Collection            Collection       `json:"collection"`
+	EventTimestamp        opensea.TimeNano `json:"event_timestamp"`
+	BasePrice             opensea.Amount   `json:"base_price"`
+	CreatedDate           opensea.TimeNano `json:"created_date"`
+	ExpirationDate        opensea.TimeNano `json:"expiration_date"`
+	Maker                 Account          `json:"maker"`
+	Taker                 *Account         `json:"taker"`
+	PaymentToken          PaymentToken     `json:"payment_token"`
+	Quantity              int64            `json:"quantity"`
+	OrderHash             string           `json:"order_hash"`
+	CollectionCriteria    *Collection      `json:"collection_criteria"`
+	AssetContractCriteria *Account         `json:"asset_contract_criteria"`
+	TraitCriteria         *TraitCriteria   `json:"trait_criteria"`
+}
+
+type TraitCriteria struct {
+	TraitType string `json:"trait_type"`
+	TraitName string `json:"trait_name"`
+}
+
+// decodePayload decodes the payload of an event of type t, labelling prices with the payment token decimals.
+func decodePayload(t opensea.EventType, raw json.RawMessage) (interface{}, error) {
+	var v interface{}
+	switch t {
+	case opensea.EventTypeItemListed:
+		v = new(ItemListed)
+	case opensea.EventTypeItemSold:
+		v = new(ItemSold)
+	case opensea.EventTypeItemTransferred:
+		v = new(ItemTransferred)
+	case opensea.EventTypeItemMetadataUpdated:
+		v = new(ItemMetadataUpdated)
+	case opensea.EventTypeItemCancelled:
+		v = new(ItemCancelled)
+	case opensea.EventTypeItemReceivedOffer, opensea.EventTypeItemReceivedBid, opensea.EventTypeCollectionOffer, opensea.EventTypeTraitOffer:
+		v = new(Offer)
+	default:
+		return nil, nil
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return nil, fmt.Errorf("decoding %s: %w", t, err)
+	}
+	switch p := v.(type) {
+	case *ItemListed:
+		p.BasePrice = withDecimals(p.BasePrice, p.PaymentToken)
+	case *ItemSold:
+		p.SalePrice = withDecimals(p.SalePrice, p.PaymentToken)
+	case *Offer:
+		p.BasePrice = withDecimals(p.BasePrice, p.PaymentToken)
+	}
+	return v, nil
+}
+
+func withDecimals(a opensea.Amount, token PaymentToken) opensea.Amount {
+	if token.Decimals > 0 {
+		return a.WithDecimals(token.Decimals)
+	}
+	return a
+}
@@ -0,0 +1,41 @@
+package stream
+
+import (
+	"encoding/json"
+)
+
+// Phoenix channel events used by the protocol itself.
+const (
+	phxJoin      = "phx_join"
+	phxLeave     = "phx_leave"
+	phxReply     = "phx_reply"
+	phxError     = "phx_error"
+	phxClose     = "phx_close"
+	phxHeartbeat = "heartbeat"
+	phxTopic     = "phoenix"
+)
+
+// Frame is one message of the Phoenix channel protocol, in its JSON object form.
+type Frame struct {
+	Topic   string          `json:"topic"`
+	Event   string          `json:"event"`
+	Payload json.RawMessage `json:"payload"`
+	Ref     *string         `json:"ref"`
+}
+
+// Reply is the payload of a phx_reply frame.
+type Reply struct {
+	Status   string          `json:"status"`
+	Response json.RawMessage `json:"response"`
+}
+
+// envelope is the payload OpenSea wraps around every stream event.
+type envelope struct {
+	EventType string          `json:"event_type"`
+	Payload   json.RawMessage `json:"payload"`
+	SentAt    string          `json:"sent_at"`
+}
+
+func collectionTopic(collection string) string {
+	return "collection:" + collection
+}
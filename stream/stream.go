@@ -0,0 +1,293 @@
+// Package stream is a client of the OpenSea Stream API, which pushes collection events over
+// a Phoenix channel WebSocket instead of having them polled from the events endpoint.
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+	"github.com/jumpblock/go-opensea"
+)
+
+const (
+	MainnetURL = "wss://stream.openseabeta.com/socket/websocket"
+	TestnetURL = "wss://testnets-stream.openseabeta.com/socket/websocket"
+)
+
+// AllCollections subscribes to the events of every collection.
+const AllCollections = "*"
+
+var errHeartbeat = errors.New("stream: heartbeat not answered")
+
+// Client keeps a WebSocket to the Stream API open while Run runs, reconnecting and joining its
+// subscriptions again whenever the connection drops.
+type Client struct {
+	URL               string
+	Heartbeat         time.Duration // interval of heartbeats, 30s if zero
+	ReconnectDelay    time.Duration // first delay before reconnecting, doubled up to MaxReconnectDelay
+	MaxReconnectDelay time.Duration
+	Dialer            *websocket.Dialer
+	// OnError is told of connection failures and of events that could not be decoded; Run carries on.
+	OnError func(error)
+
+	mu      sync.Mutex
+	subs    map[string][]*subscription
+	conn    *websocket.Conn
+	writeMu sync.Mutex
+	ref     int64
+}
+
+type subscription struct {
+	handler func(*Event)
+	types   map[opensea.EventType]bool
+}
+
+func NewClient(apiKey string) *Client {
+	return &Client{
+		URL:  MainnetURL + "?token=" + url.QueryEscape(apiKey),
+		subs: map[string][]*subscription{},
+	}
+}
+
+// Subscribe calls handler with the events of collection, a slug or AllCollections, restricted to types
+// when given. It may be called before or while Run runs; the returned function unsubscribes.
+func (c *Client) Subscribe(collection string, handler func(*Event), types ...opensea.EventType) (unsubscribe func()) {
+	sub := &subscription{handler: handler}
+	if len(types) > 0 {
+		sub.types = map[opensea.EventType]bool{}
+		for _, t := range types {
+			sub.types[t] = true
+		}
+	}
+	topic := collectionTopic(collection)
+
+	c.mu.Lock()
+	if c.subs == nil {
+		c.subs = map[string][]*subscription{}
+	}
+	join := len(c.subs[topic]) == 0
+	c.subs[topic] = append(c.subs[topic], sub)
+	conn := c.conn
+	c.mu.Unlock()
+	if join && conn != nil {
+		c.send(conn, topic, phxJoin)
+	}
+
+	return func() {
+		c.mu.Lock()
+		subs := c.subs[topic]
+		for i, v := range subs {
+			if v == sub {
+				subs = append(subs[:i:i], subs[i+1:]...)
+				break
+			}
+		}
+		leave := len(subs) == 0 && len(c.subs[topic]) > 0
+		if len(subs) == 0 {
+			delete(c.subs, topic)
+		} else {
+			c.subs[topic] = subs
+		}
+		conn := c.conn
+		c.mu.Unlock()
+		if leave && conn != nil {
+			c.send(conn, topic, phxLeave)
+		}
+	}
+}
+
+// Run connects and dispatches events until ctx is done, reconnecting with backoff when the connection
+// fails. Handlers are called one at a time from Run's goroutine.
+func (c *Client) Run(ctx context.Context) error {
+	delay := c.reconnectDelay()
+	for {
+		connected, err := c.runOnce(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		c.report(err)
+		if connected {
+			delay = c.reconnectDelay()
+		}
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		delay *= 2
+		if max := c.maxReconnectDelay(); delay > max {
+			delay = max
+		}
+	}
+}
+
+// runOnce serves one connection until it fails, reporting whether it was established.
+func (c *Client) runOnce(ctx context.Context) (bool, error) {
+	dialer := c.Dialer
+	if dialer == nil {
+		dialer = websocket.DefaultDialer
+	}
+	conn, _, err := dialer.DialContext(ctx, c.URL, nil)
+	if err != nil {
+		return false, err
+	}
+	defer conn.Close()
+
+	c.mu.Lock()
+	c.conn = conn
+	topics := make([]string, 0, len(c.subs))
+	for topic := range c.subs {
+		topics = append(topics, topic)
+	}
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		c.conn = nil
+		c.mu.Unlock()
+	}()
+	for _, topic := range topics {
+		if err := c.send(conn, topic, phxJoin); err != nil {
+			return true, err
+		}
+	}
+
+	// done stops the reader when this connection is given up, even while it holds a frame.
+	done := make(chan struct{})
+	defer close(done)
+	frames := make(chan Frame)
+	readErr := make(chan error, 1)
+	go func() {
+		for {
+			var f Frame
+			if err := conn.ReadJSON(&f); err != nil {
+				readErr <- err
+				return
+			}
+			select {
+			case frames <- f:
+			case <-done:
+				return
+			}
+		}
+	}()
+
+	ticker := time.NewTicker(c.heartbeat())
+	defer ticker.Stop()
+	pending := ""
+	for {
+		select {
+		case <-ctx.Done():
+			conn.WriteControl(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseNormalClosure, ""), time.Now().Add(time.Second))
+			return true, ctx.Err()
+		case err := <-readErr:
+			return true, err
+		case <-ticker.C:
+			if pending != "" {
+				return true, errHeartbeat
+			}
+			ref, err := c.sendRef(conn, phxTopic, phxHeartbeat)
+			if err != nil {
+				return true, err
+			}
+			pending = ref
+		case f := <-frames:
+			if f.Event == phxReply && f.Topic == phxTopic && f.Ref != nil && *f.Ref == pending {
+				pending = ""
+				continue
+			}
+			c.dispatch(f)
+		}
+	}
+}
+
+func (c *Client) dispatch(f Frame) {
+	switch f.Event {
+	case phxReply:
+		var r Reply
+		if err := json.Unmarshal(f.Payload, &r); err == nil && r.Status != "ok" {
+			c.report(fmt.Errorf("stream: %s replied %s: %s", f.Topic, r.Status, string(r.Response)))
+		}
+		return
+	case phxError, phxClose:
+		c.report(fmt.Errorf("stream: channel %s: %s", f.Topic, f.Event))
+		return
+	}
+
+	var env envelope
+	if err := json.Unmarshal(f.Payload, &env); err != nil {
+		c.report(fmt.Errorf("stream: decoding %s: %w", f.Event, err))
+		return
+	}
+	e := &Event{Type: opensea.EventType(f.Event), Raw: env.Payload}
+	if env.EventType != "" {
+		e.Type = opensea.EventType(env.EventType)
+	}
+	e.SentAt, _ = opensea.ParseTime(env.SentAt)
+	payload, err := decodePayload(e.Type, env.Payload)
+	if err != nil {
+		c.report(err)
+		return
+	}
+	e.Payload = payload
+	var slug struct {
+		Collection Collection `json:"collection"`
+	}
+	json.Unmarshal(env.Payload, &slug)
+	e.Collection = slug.Collection.Slug
+
+	c.mu.Lock()
+	subs := append([]*subscription{}, c.subs[f.Topic]...)
+	c.mu.Unlock()
+	for _, sub := range subs {
+		if sub.types == nil || sub.types[e.Type] {
+			sub.handler(e)
+		}
+	}
+}
+
+func (c *Client) send(conn *websocket.Conn, topic, event string) error {
+	_, err := c.sendRef(conn, topic, event)
+	return err
+}
+
+func (c *Client) sendRef(conn *websocket.Conn, topic, event string) (string, error) {
+	c.writeMu.Lock()
+	defer c.writeMu.Unlock()
+	c.ref++
+	ref := strconv.FormatInt(c.ref, 10)
+	return ref, conn.WriteJSON(Frame{Topic: topic, Event: event, Payload: json.RawMessage(`{}`), Ref: &ref})
+}
+
+func (c *Client) report(err error) {
+	if err != nil && c.OnError != nil {
+		c.OnError(err)
+	}
+}
+
+func (c *Client) heartbeat() time.Duration {
+	if c.Heartbeat > 0 {
+		return c.Heartbeat
+	}
+	return 30 * time.Second
+}
+
+func (c *Client) reconnectDelay() time.Duration {
+	if c.ReconnectDelay > 0 {
+		return c.ReconnectDelay
+	}
+	return time.Second
+}
+
+func (c *Client) maxReconnectDelay() time.Duration {
+	if c.MaxReconnectDelay > 0 {
+		return c.MaxReconnectDelay
+	}
+	return time.Minute
+}
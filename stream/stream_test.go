@@ -0,0 +1,170 @@
+package stream
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/jumpblock/go-opensea"
+	"github.com/jumpblock/go-opensea/stream/streamtest"
+	"github.com/stretchr/testify/assert"
+)
+
+var listedPayload = map[string]interface{}{
+	"event_timestamp": "2022-10-04T20:53:12.116405+00:00",
+	"base_price":      "2500000",
+	"collection":      map[string]interface{}{"slug": "doodles-official"},
+	"listing_date":    "2022-10-04T20:53:11.000000+00:00",
+	"expiration_date": "2022-11-04T20:53:11.000000+00:00",
+	"listing_type":    nil,
+	"is_private":      false,
+	"maker":           map[string]interface{}{"address": "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed"},
+	"taker":           nil,
+	"payment_token": map[string]interface{}{
+		"address": "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48", "decimals": 6,
+		"eth_price": "0.000750000000000000", "name": "USD Coin", "symbol": "USDC", "usd_price": "1.000000000000000000",
+	},
+	"quantity":   1,
+	"order_hash": "0xabc",
+	"item": map[string]interface{}{
+		"nft_id":    "ethereum/0x8a90cab2b38dba80c64b7734e58ee1db38b8992e/1234",
+		"permalink": "https://opensea.io/assets/ethereum/0x8a90cab2b38dba80c64b7734e58ee1db38b8992e/1234",
+		"metadata":  map[string]interface{}{"name": "Doodle #1234"},
+		"chain":     map[string]interface{}{"name": "ethereum"},
+	},
+}
+
+func startClient(t *testing.T, srv *streamtest.Server, setup func(c *Client)) (*Client, chan error) {
+	c := NewClient("key")
+	c.URL = srv.URL()
+	c.ReconnectDelay = 10 * time.Millisecond
+	setup(c)
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- c.Run(ctx) }()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+		srv.Close()
+	})
+	return c, done
+}
+
+func receive(t *testing.T, events chan *Event) *Event {
+	select {
+	case e := <-events:
+		return e
+	case <-time.After(2 * time.Second):
+		t.Fatal("no event received")
+		return nil
+	}
+}
+
+func TestStreamDecodesEvents(t *testing.T) {
+	srv := streamtest.NewServer()
+	events := make(chan *Event, 10)
+	startClient(t, srv, func(c *Client) {
+		c.Subscribe("doodles-official", func(e *Event) { events <- e })
+	})
+	assert.Equal(t, "collection:doodles-official", srv.WaitJoin(2*time.Second))
+
+	assert.Equal(t, 1, srv.Broadcast("collection:doodles-official", "item_listed", listedPayload))
+	e := receive(t, events)
+	assert.Equal(t, opensea.EventTypeItemListed, e.Type)
+	assert.Equal(t, "doodles-official", e.Collection)
+	assert.False(t, e.SentAt.IsZero())
+	listed, ok := e.Payload.(*ItemListed)
+	if assert.True(t, ok) {
+		assert.Equal(t, "2.5", listed.BasePrice.Units())
+		assert.Equal(t, "USDC", listed.PaymentToken.Symbol)
+		assert.Nil(t, listed.Taker)
+		assert.Equal(t, "Doodle #1234", listed.Item.Metadata.Name)
+		assert.Equal(t, int64(1664916791), listed.ListingDate.Unix())
+	}
+
+	srv.Broadcast("collection:doodles-official", "trait_offer", map[string]interface{}{
+		"base_price":     "1000000000000000000",
+		"collection":     map[string]interface{}{"slug": "doodles-official"},
+		"trait_criteria": map[string]interface{}{"trait_type": "face", "trait_name": "happy"},
+	})
+	e = receive(t, events)
+	offer := e.Payload.(*Offer)
+	assert.Equal(t, "happy", offer.TraitCriteria.TraitName)
+	assert.Equal(t, "1", offer.BasePrice.Units())
+
+	srv.Broadcast("collection:doodles-official", "something_new", map[string]interface{}{"a": 1})
+	e = receive(t, events)
+	assert.Nil(t, e.Payload)
+	assert.JSONEq(t, `{"a": 1}`, string(e.Raw))
+}
+
+func TestStreamFiltersAndUnsubscribes(t *testing.T) {
+	srv := streamtest.NewServer()
+	sold := make(chan *Event, 10)
+	all := make(chan *Event, 10)
+	var unsubscribe func()
+	startClient(t, srv, func(c *Client) {
+		c.Subscribe(AllCollections, func(e *Event) { sold <- e }, opensea.EventTypeItemSold)
+		unsubscribe = c.Subscribe("doodles-official", func(e *Event) { all <- e })
+	})
+	joined := map[string]bool{srv.WaitJoin(2 * time.Second): true, srv.WaitJoin(2 * time.Second): true}
+	assert.True(t, joined["collection:*"])
+	assert.True(t, joined["collection:doodles-official"])
+
+	srv.Broadcast("collection:doodles-official", "item_listed", listedPayload)
+	srv.Broadcast("collection:doodles-official", "item_sold", map[string]interface{}{"sale_price": "1"})
+	assert.Equal(t, opensea.EventTypeItemListed, receive(t, all).Type)
+	assert.Equal(t, opensea.EventTypeItemSold, receive(t, all).Type)
+	assert.Equal(t, opensea.EventTypeItemSold, receive(t, sold).Type)
+
+	unsubscribe()
+	time.Sleep(50 * time.Millisecond)
+	srv.Broadcast("collection:doodles-official", "item_sold", map[string]interface{}{"sale_price": "1"})
+	assert.Equal(t, opensea.EventTypeItemSold, receive(t, sold).Type)
+	select {
+	case <-all:
+		t.Fatal("event delivered after unsubscribe")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestStreamReconnectsAndResubscribes(t *testing.T) {
+	srv := streamtest.NewServer()
+	events := make(chan *Event, 10)
+	errs := make(chan error, 10)
+	startClient(t, srv, func(c *Client) {
+		c.OnError = func(err error) { errs <- err }
+		c.Subscribe("doodles-official", func(e *Event) { events <- e })
+	})
+	assert.Equal(t, "collection:doodles-official", srv.WaitJoin(2*time.Second))
+
+	srv.DropConnections()
+	assert.Equal(t, "collection:doodles-official", srv.WaitJoin(2*time.Second))
+	assert.Equal(t, 2, srv.Connections())
+	assert.NotNil(t, <-errs)
+
+	srv.Broadcast("collection:doodles-official", "item_listed", listedPayload)
+	assert.Equal(t, opensea.EventTypeItemListed, receive(t, events).Type)
+}
+
+func TestStreamHeartbeats(t *testing.T) {
+	srv := streamtest.NewServer()
+	srv.IgnoreHeartbeats = true
+	errs := make(chan error, 10)
+	startClient(t, srv, func(c *Client) {
+		c.Heartbeat = 20 * time.Millisecond
+		c.OnError = func(err error) { errs <- err }
+		c.Subscribe("doodles-official", func(e *Event) {})
+	})
+	srv.WaitJoin(2 * time.Second)
+
+	// an unanswered heartbeat is a dead connection
+	select {
+	case err := <-errs:
+		assert.Equal(t, errHeartbeat, err)
+	case <-time.After(2 * time.Second):
+		t.Fatal("dead connection not detected")
+	}
+	assert.Equal(t, "collection:doodles-official", srv.WaitJoin(2*time.Second))
+	assert.True(t, srv.Heartbeats() >= 1)
+}
@@ -0,0 +1,166 @@
+// Package streamtest provides an in-process stand-in for the OpenSea Stream API, speaking enough of
+// the Phoenix channel protocol to test stream clients offline.
+package streamtest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gorilla/websocket"
+)
+
+type frame struct {
+	Topic   string          `json:"topic"`
+	Event   string          `json:"event"`
+	Payload json.RawMessage `json:"payload"`
+	Ref     *string         `json:"ref"`
+}
+
+// Server accepts stream connections, answers joins and heartbeats, and broadcasts events to the
+// connections that joined their topic.
+type Server struct {
+	*httptest.Server
+	// IgnoreHeartbeats stops the server from answering heartbeats, as a dead connection would.
+	IgnoreHeartbeats bool
+
+	mu          sync.Mutex
+	conns       map[*websocket.Conn]*peer
+	connections int
+	heartbeats  int
+	joined      chan string
+	upgrader    websocket.Upgrader
+}
+
+type peer struct {
+	writeMu sync.Mutex
+	topics  map[string]bool
+}
+
+func NewServer() *Server {
+	s := &Server{conns: map[*websocket.Conn]*peer{}, joined: make(chan string, 100)}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serve))
+	return s
+}
+
+// URL returns the WebSocket URL of the server.
+func (s *Server) URL() string {
+	return "ws" + strings.TrimPrefix(s.Server.URL, "http") + "/socket/websocket"
+}
+
+func (s *Server) serve(w http.ResponseWriter, r *http.Request) {
+	conn, err := s.upgrader.Upgrade(w, r, nil)
+	if err != nil {
+		return
+	}
+	p := &peer{topics: map[string]bool{}}
+	s.mu.Lock()
+	s.conns[conn] = p
+	s.connections++
+	s.mu.Unlock()
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		conn.Close()
+	}()
+
+	for {
+		var f frame
+		if err := conn.ReadJSON(&f); err != nil {
+			return
+		}
+		switch f.Event {
+		case "heartbeat":
+			s.mu.Lock()
+			s.heartbeats++
+			ignore := s.IgnoreHeartbeats
+			s.mu.Unlock()
+			if !ignore {
+				s.reply(conn, p, f)
+			}
+		case "phx_join":
+			s.mu.Lock()
+			p.topics[f.Topic] = true
+			s.mu.Unlock()
+			s.reply(conn, p, f)
+			s.joined <- f.Topic
+		case "phx_leave":
+			s.mu.Lock()
+			delete(p.topics, f.Topic)
+			s.mu.Unlock()
+			s.reply(conn, p, f)
+		}
+	}
+}
+
+func (s *Server) reply(conn *websocket.Conn, p *peer, f frame) {
+	p.writeMu.Lock()
+	defer p.writeMu.Unlock()
+	conn.WriteJSON(frame{Topic: f.Topic, Event: "phx_reply", Payload: json.RawMessage(`{"status":"ok","response":{}}`), Ref: f.Ref})
+}
+
+// WaitJoin waits for the next join and returns its topic, or "" after timeout.
+func (s *Server) WaitJoin(timeout time.Duration) string {
+	select {
+	case topic := <-s.joined:
+		return topic
+	case <-time.After(timeout):
+		return ""
+	}
+}
+
+// Broadcast sends an event to the connections joined to topic, or to "collection:*", wrapped as OpenSea does.
+// It returns the number of connections it reached.
+func (s *Server) Broadcast(topic, eventType string, payload interface{}) int {
+	inner, err := json.Marshal(payload)
+	if err != nil {
+		panic(err)
+	}
+	env, _ := json.Marshal(map[string]interface{}{
+		"event_type": eventType,
+		"payload":    json.RawMessage(inner),
+		"sent_at":    time.Now().UTC().Format(time.RFC3339Nano),
+	})
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	n := 0
+	for conn, p := range s.conns {
+		for _, t := range []string{topic, "collection:*"} {
+			if !p.topics[t] {
+				continue
+			}
+			p.writeMu.Lock()
+			conn.WriteJSON(frame{Topic: t, Event: eventType, Payload: env})
+			p.writeMu.Unlock()
+			n++
+		}
+	}
+	return n
+}
+
+// DropConnections closes every open connection, as a network failure would.
+func (s *Server) DropConnections() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for conn := range s.conns {
+		conn.Close()
+	}
+}
+
+// Connections counts the connections accepted so far.
+func (s *Server) Connections() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.connections
+}
+
+// Heartbeats counts the heartbeats received so far.
+func (s *Server) Heartbeats() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.heartbeats
+}
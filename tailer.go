@@ -0,0 +1,261 @@
+package opensea
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// Checkpoint is where an EventTailer resumes: the time of the newest event it delivered, and the ids of
+// the delivered events recent enough to be returned again by the next, overlapping, poll. A Crawler
+// resumes at Cursor.
+type Checkpoint struct {
+	Latest time.Time   `json:"latest" bson:"latest"`
+	Seen   []SeenEvent `json:"seen" bson:"seen"`
+	Cursor string      `json:"cursor,omitempty" bson:"cursor,omitempty"`
+}
+
+// SeenEvent is a delivered event, kept until it is older than the overlap of the polls.
+type SeenEvent struct {
+	ID uint64    `json:"id" bson:"id"`
+	At time.Time `json:"at" bson:"at"`
+}
+
+// UnmarshalJSON also accepts the bare id that checkpoints saved before SeenEvent hold;
+// those have a zero At and are dropped by the next poll that does not return them.
+func (s *SeenEvent) UnmarshalJSON(b []byte) error {
+	var id uint64
+	if err := json.Unmarshal(b, &id); err == nil {
+		*s = SeenEvent{ID: id}
+		return nil
+	}
+	type seenEvent SeenEvent
+	return json.Unmarshal(b, (*seenEvent)(s))
+}
+
+// CheckpointStore persists the checkpoints of tailers by key. Load returns nil without error when
+// there is no checkpoint yet.
+type CheckpointStore interface {
+	Load(ctx context.Context, key string) (*Checkpoint, error)
+	Save(ctx context.Context, key string, cp Checkpoint) error
+}
+
+// MemoryCheckpointStore keeps checkpoints for the life of the process.
+type MemoryCheckpointStore struct {
+	mu  sync.Mutex
+	cps map[string]Checkpoint
+}
+
+func NewMemoryCheckpointStore() *MemoryCheckpointStore {
+	return &MemoryCheckpointStore{cps: map[string]Checkpoint{}}
+}
+
+func (s *MemoryCheckpointStore) Load(ctx context.Context, key string) (*Checkpoint, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cp, ok := s.cps[key]
+	if !ok {
+		return nil, nil
+	}
+	cp.Seen = append([]SeenEvent{}, cp.Seen...)
+	return &cp, nil
+}
+
+func (s *MemoryCheckpointStore) Save(ctx context.Context, key string, cp Checkpoint) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	cp.Seen = append([]SeenEvent{}, cp.Seen...)
+	s.cps[key] = cp
+	return nil
+}
+
+// FileCheckpointStore keeps each checkpoint in a JSON file of a directory.
+type FileCheckpointStore struct {
+	Dir string
+}
+
+func (s FileCheckpointStore) file(key string) string {
+	return filepath.Join(s.Dir, filepath.Base(key)+".checkpoint.json")
+}
+
+func (s FileCheckpointStore) Load(ctx context.Context, key string) (*Checkpoint, error) {
+	b, err := ioutil.ReadFile(s.file(key))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	cp := new(Checkpoint)
+	return cp, json.Unmarshal(b, cp)
+}
+
+// Save replaces the checkpoint file atomically, so that a crash leaves the previous checkpoint.
+func (s FileCheckpointStore) Save(ctx context.Context, key string, cp Checkpoint) error {
+	b, err := json.Marshal(cp)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(s.Dir, 0o755); err != nil {
+		return err
+	}
+	tmp, err := ioutil.TempFile(s.Dir, "tmp-")
+	if err != nil {
+		return err
+	}
+	_, err = tmp.Write(b)
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp.Name(), s.file(key))
+	}
+	if err != nil {
+		os.Remove(tmp.Name())
+	}
+	return err
+}
+
+// OccurredAt is the time of the event, falling back to when OpenSea recorded it.
+func (e Event) OccurredAt() time.Time {
+	if !e.EventTimestamp.IsZero() {
+		return e.EventTimestamp.Time()
+	}
+	return e.CreatedDate.Time()
+}
+
+// EventTailer polls the events endpoint and hands each new event to Handler once, oldest first.
+// Every poll reaches back Overlap before the newest event delivered, so that events indexed late or
+// split over page boundaries are not missed, and drops the events it delivered already.
+type EventTailer struct {
+	Client Opensea
+	Params EventParams // filters of the polls; OccurredAfter is where a tailer without checkpoint starts
+	Key    string      // name of the checkpoint in Store
+	Store  CheckpointStore
+	// Handler receives the new events; when it fails the event is delivered again by the next poll.
+	Handler  func(ctx context.Context, e *Event) error
+	Interval time.Duration // between polls, 1 minute if zero
+	Overlap  time.Duration // 5 minutes if zero
+	OnError  func(error)   // told of failed polls; Run carries on
+
+	cp *Checkpoint
+}
+
+func NewEventTailer(o Opensea, key string, params EventParams, store CheckpointStore, handler func(ctx context.Context, e *Event) error) *EventTailer {
+	return &EventTailer{Client: o, Params: params, Key: key, Store: store, Handler: handler}
+}
+
+// Run polls every Interval until ctx is done.
+func (t *EventTailer) Run(ctx context.Context) error {
+	interval := t.Interval
+	if interval <= 0 {
+		interval = time.Minute
+	}
+	for {
+		if _, err := t.Poll(ctx); err != nil && ctx.Err() == nil && t.OnError != nil {
+			t.OnError(err)
+		}
+		select {
+		case <-time.After(interval):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// Poll fetches the events since the checkpoint, delivers the new ones and saves the checkpoint.
+// It returns the number of events delivered.
+func (t *EventTailer) Poll(ctx context.Context) (int, error) {
+	if t.cp == nil {
+		cp, err := t.Store.Load(ctx, t.Key)
+		if err != nil {
+			return 0, err
+		}
+		if cp == nil {
+			cp = &Checkpoint{}
+			if t.Params.OccurredAfter != 0 {
+				cp.Latest = time.Unix(t.Params.OccurredAfter, 0).UTC()
+			}
+		}
+		t.cp = cp
+	}
+	overlap := t.Overlap
+	if overlap <= 0 {
+		overlap = 5 * time.Minute
+	}
+
+	params := t.Params
+	params.Cursor = ""
+	if !t.cp.Latest.IsZero() {
+		params.OccurredAfter = t.cp.Latest.Add(-overlap).Unix()
+	}
+	if params.Limit == 0 {
+		params.Limit = 300
+	}
+	events, err := t.Client.RetrievingEventsWithContext(ctx, &params)
+	if err != nil {
+		return 0, err
+	}
+
+	seen := map[uint64]bool{}
+	for _, v := range t.cp.Seen {
+		seen[v.ID] = true
+	}
+	var fresh []*Event
+	for _, e := range events {
+		if !seen[e.ID] {
+			seen[e.ID] = true
+			fresh = append(fresh, e)
+		}
+	}
+	sort.SliceStable(fresh, func(i, j int) bool {
+		a, b := fresh[i].OccurredAt(), fresh[j].OccurredAt()
+		if !a.Equal(b) {
+			return a.Before(b)
+		}
+		return fresh[i].ID < fresh[j].ID
+	})
+
+	delivered := 0
+	var handlerErr error
+	for _, e := range fresh {
+		if err := t.Handler(ctx, e); err != nil {
+			handlerErr = err
+			break
+		}
+		t.cp.Seen = append(t.cp.Seen, SeenEvent{ID: e.ID, At: e.OccurredAt()})
+		if at := e.OccurredAt(); at.After(t.cp.Latest) {
+			t.cp.Latest = at
+		}
+		delivered++
+	}
+
+	// only the events that the next poll can return again are worth keeping
+	cutoff := t.cp.Latest.Add(-overlap - time.Second)
+	at := map[uint64]time.Time{}
+	for _, e := range events {
+		at[e.ID] = e.OccurredAt()
+	}
+	kept := t.cp.Seen[:0]
+	for _, v := range t.cp.Seen {
+		if v.At.IsZero() {
+			v.At = at[v.ID]
+		}
+		if !v.At.IsZero() && !v.At.Before(cutoff) {
+			kept = append(kept, v)
+		}
+	}
+	t.cp.Seen = kept
+
+	if delivered > 0 {
+		if err := t.Store.Save(ctx, t.Key, *t.cp); err != nil {
+			return delivered, err
+		}
+	}
+	return delivered, handlerErr
+}
@@ -0,0 +1,187 @@
+package opensea
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+type fakeEvent struct {
+	ID uint64
+	At time.Time
+}
+
+// eventsServer serves the events occurred after the occurred_after parameter, newest first.
+type eventsServer struct {
+	mu     sync.Mutex
+	events []fakeEvent
+	after  []int64
+}
+
+func (s *eventsServer) add(id uint64, at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.events = append([]fakeEvent{{id, at}}, s.events...)
+}
+
+func (s *eventsServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	after, _ := strconv.ParseInt(r.URL.Query().Get("occurred_after"), 10, 64)
+	s.after = append(s.after, after)
+	var out []map[string]interface{}
+	for _, e := range s.events {
+		if e.At.Unix() >= after {
+			out = append(out, map[string]interface{}{
+				"id":              e.ID,
+				"event_type":      "successful",
+				"event_timestamp": e.At.Format("2006-01-02T15:04:05"),
+			})
+		}
+	}
+	json.NewEncoder(w).Encode(map[string]interface{}{"asset_events": out})
+}
+
+func TestEventTailer(t *testing.T) {
+	base := time.Date(2022, 4, 16, 12, 0, 0, 0, time.UTC)
+	es := &eventsServer{}
+	es.add(1, base)
+	es.add(3, base.Add(time.Minute))
+	es.add(2, base.Add(time.Minute))
+	srv := httptest.NewServer(es)
+	defer srv.Close()
+
+	var got []uint64
+	handler := func(ctx context.Context, e *Event) error {
+		got = append(got, e.ID)
+		return nil
+	}
+	store := NewMemoryCheckpointStore()
+	tailer := NewEventTailer(testClient(srv), "sales", EventParams{}, store, handler)
+	tailer.Overlap = 10 * time.Minute
+
+	n, err := tailer.Poll(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, 3, n)
+	assert.Equal(t, []uint64{1, 2, 3}, got)
+
+	// a late event inside the overlap window is delivered, the ones already seen are not
+	es.add(4, base.Add(30*time.Second))
+	es.add(5, base.Add(2*time.Minute))
+	n, err = tailer.Poll(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, 2, n)
+	assert.Equal(t, []uint64{1, 2, 3, 4, 5}, got)
+	assert.Equal(t, base.Add(time.Minute-10*time.Minute).Unix(), es.after[1])
+
+	// a new tailer on the same store resumes without replaying
+	es.add(6, base.Add(3*time.Minute))
+	restarted := NewEventTailer(testClient(srv), "sales", EventParams{}, store, handler)
+	restarted.Overlap = 10 * time.Minute
+	n, err = restarted.Poll(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, []uint64{1, 2, 3, 4, 5, 6}, got)
+
+	cp, _ := store.Load(context.Background(), "sales")
+	assert.Equal(t, base.Add(3*time.Minute), cp.Latest)
+	assert.ElementsMatch(t, []uint64{1, 2, 3, 4, 5, 6}, seenIDs(cp))
+}
+
+func seenIDs(cp *Checkpoint) []uint64 {
+	var ids []uint64
+	for _, v := range cp.Seen {
+		ids = append(ids, v.ID)
+	}
+	return ids
+}
+
+func TestEventTailerSeenStaysBounded(t *testing.T) {
+	base := time.Date(2022, 4, 16, 12, 0, 0, 0, time.UTC)
+	es := &eventsServer{}
+	srv := httptest.NewServer(es)
+	defer srv.Close()
+
+	store := NewMemoryCheckpointStore()
+	tailer := NewEventTailer(testClient(srv), "sales", EventParams{}, store, func(ctx context.Context, e *Event) error {
+		return nil
+	})
+	tailer.Overlap = 5 * time.Minute
+
+	// two events a minute for two hours; the overlap covers at most six minutes of them
+	for i := 0; i < 120; i++ {
+		at := base.Add(time.Duration(i) * time.Minute)
+		es.add(uint64(2*i+1), at)
+		es.add(uint64(2*i+2), at.Add(30*time.Second))
+		n, err := tailer.Poll(context.Background())
+		assert.Nil(t, err)
+		assert.Equal(t, 2, n)
+
+		cp, _ := store.Load(context.Background(), "sales")
+		assert.True(t, len(cp.Seen) <= 12, len(cp.Seen))
+		for _, v := range cp.Seen {
+			assert.False(t, v.At.Before(cp.Latest.Add(-tailer.Overlap-time.Second)))
+		}
+	}
+}
+
+func TestEventTailerHandlerError(t *testing.T) {
+	base := time.Date(2022, 4, 16, 12, 0, 0, 0, time.UTC)
+	es := &eventsServer{}
+	es.add(1, base)
+	es.add(2, base.Add(time.Minute))
+	srv := httptest.NewServer(es)
+	defer srv.Close()
+
+	fail := true
+	var got []uint64
+	tailer := NewEventTailer(testClient(srv), "sales", EventParams{}, FileCheckpointStore{Dir: t.TempDir()}, func(ctx context.Context, e *Event) error {
+		if e.ID == 2 && fail {
+			return errors.New("unavailable")
+		}
+		got = append(got, e.ID)
+		return nil
+	})
+
+	n, err := tailer.Poll(context.Background())
+	assert.EqualError(t, err, "unavailable")
+	assert.Equal(t, 1, n)
+
+	// the failed event is retried by the next poll
+	fail = false
+	n, err = tailer.Poll(context.Background())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, n)
+	assert.Equal(t, []uint64{1, 2}, got)
+}
+
+func TestFileCheckpointStore(t *testing.T) {
+	store := FileCheckpointStore{Dir: t.TempDir()}
+	ctx := context.Background()
+
+	cp, err := store.Load(ctx, "sales")
+	assert.Nil(t, err)
+	assert.Nil(t, cp)
+
+	latest := time.Date(2022, 4, 16, 12, 0, 0, 0, time.UTC)
+	want := Checkpoint{Latest: latest, Seen: []SeenEvent{{7, latest.Add(-time.Minute)}, {8, latest}}}
+	assert.Nil(t, store.Save(ctx, "sales", want))
+	cp, err = store.Load(ctx, "sales")
+	assert.Nil(t, err)
+	assert.Equal(t, want, *cp)
+
+	// checkpoints saved with bare ids still load
+	assert.Nil(t, ioutil.WriteFile(store.file("old"), []byte(`{"latest":"2022-04-16T12:00:00Z","seen":[7,8]}`), 0o644))
+	cp, err = store.Load(ctx, "old")
+	assert.Nil(t, err)
+	assert.Equal(t, []SeenEvent{{ID: 7}, {ID: 8}}, cp.Seen)
+}
@@ -0,0 +1,170 @@
+package opensea
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Chain is the chain identifier OpenSea uses in its v2 paths, such as /v2/orders/{chain}/seaport/listings.
+type Chain string
+
+const (
+	ChainEthereum Chain = "ethereum"
+	ChainPolygon  Chain = "matic"
+	ChainRinkeby  Chain = "rinkeby"
+	ChainGoerli   Chain = "goerli"
+)
+
+// TokenInfo describes a payment token on one chain.
+type TokenInfo struct {
+	Chain    Chain
+	Symbol   string
+	Name     string
+	Address  Address
+	Decimals int
+}
+
+// Amount parses base units of the token.
+func (t TokenInfo) Amount(base string) (Amount, error) {
+	return ParseAmount(base, t.Decimals)
+}
+
+// Units parses a human readable amount of the token, such as "0.5".
+func (t TokenInfo) Units(s string) (Amount, error) {
+	return ParseUnits(s, t.Decimals)
+}
+
+func (t TokenInfo) IsNative() bool {
+	return t.Address.IsNullAddress()
+}
+
+// TokenRegistry knows the payment tokens of each chain by address and by symbol.
+type TokenRegistry struct {
+	mu        sync.RWMutex
+	byAddress map[Chain]map[Address]TokenInfo
+	bySymbol  map[Chain]map[string]TokenInfo
+}
+
+var builtinTokens = []TokenInfo{
+	{ChainEthereum, "ETH", "Ether", NullAddress, 18},
+	{ChainEthereum, "WETH", "Wrapped Ether", "0xc02aaa39b223fe8d0a0e5c4f27ead9083c756cc2", 18},
+	{ChainEthereum, "USDC", "USD Coin", "0xa0b86991c6218b36c1d19d4a2e9eb0ce3606eb48", 6},
+	{ChainEthereum, "DAI", "Dai Stablecoin", "0x6b175474e89094c44da98b954eedeac495271d0f", 18},
+	{ChainEthereum, "APE", "ApeCoin", "0x4d224452801aced8b2f0aebe155379bb5d594381", 18},
+	{ChainEthereum, "MATIC", "Matic Token", "0x7d1afa7b718fb893db30a3abc0cfc608aacfebb0", 18},
+
+	{ChainPolygon, "MATIC", "Matic", NullAddress, 18},
+	{ChainPolygon, "WMATIC", "Wrapped Matic", "0x0d500b1d8e8ef31e21c99d1db9a6444d3adf1270", 18},
+	{ChainPolygon, "WETH", "Wrapped Ether", "0x7ceb23fd6bc0add59e62ac25578270cff1b9f619", 18},
+	{ChainPolygon, "USDC", "USD Coin", "0x2791bca1f2de4661ed88a30c99a7a9449aa84174", 6},
+	{ChainPolygon, "DAI", "Dai Stablecoin", "0x8f3cf7ad23cd3cadbd9735aff958023239c6a063", 18},
+
+	{ChainRinkeby, "ETH", "Ether", NullAddress, 18},
+	{ChainRinkeby, "WETH", "Wrapped Ether", "0xc778417e063141139fce010982780140aa0cd5ab", 18},
+
+	{ChainGoerli, "ETH", "Ether", NullAddress, 18},
+	{ChainGoerli, "WETH", "Wrapped Ether", "0xb4fbf271143f4fbf7b91a5ded31805e42b2208d6", 18},
+}
+
+// DefaultTokenRegistry holds the built-in tokens; register more on it at start up.
+var DefaultTokenRegistry = NewTokenRegistry()
+
+// NewTokenRegistry returns a registry of the built-in tokens.
+func NewTokenRegistry() *TokenRegistry {
+	r := &TokenRegistry{
+		byAddress: map[Chain]map[Address]TokenInfo{},
+		bySymbol:  map[Chain]map[string]TokenInfo{},
+	}
+	for _, v := range builtinTokens {
+		r.Register(v)
+	}
+	return r
+}
+
+// Register adds a token, replacing any with the same address or symbol on its chain.
+func (r *TokenRegistry) Register(t TokenInfo) {
+	t.Address = Address(strings.ToLower(t.Address.String()))
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.byAddress[t.Chain] == nil {
+		r.byAddress[t.Chain] = map[Address]TokenInfo{}
+		r.bySymbol[t.Chain] = map[string]TokenInfo{}
+	}
+	r.byAddress[t.Chain][t.Address] = t
+	r.bySymbol[t.Chain][strings.ToUpper(t.Symbol)] = t
+}
+
+func (r *TokenRegistry) ByAddress(chain Chain, address Address) (TokenInfo, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	t, ok := r.byAddress[chain][Address(strings.ToLower(address.String()))]
+	return t, ok
+}
+
+func (r *TokenRegistry) BySymbol(chain Chain, symbol string) (TokenInfo, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	t, ok := r.bySymbol[chain][strings.ToUpper(symbol)]
+	return t, ok
+}
+
+// Resolve finds a token from either its address or its symbol.
+func (r *TokenRegistry) Resolve(chain Chain, symbolOrAddress string) (TokenInfo, error) {
+	if strings.HasPrefix(symbolOrAddress, "0x") {
+		addr, err := ParseAddress(symbolOrAddress)
+		if err != nil {
+			return TokenInfo{}, err
+		}
+		if t, ok := r.ByAddress(chain, addr); ok {
+			return t, nil
+		}
+	} else if t, ok := r.BySymbol(chain, symbolOrAddress); ok {
+		return t, nil
+	}
+	return TokenInfo{}, fmt.Errorf("unknown payment token %s on %s", symbolOrAddress, chain)
+}
+
+// OrderPrice returns the current price of o labelled with the decimals of the token it is paid in.
+func (r *TokenRegistry) OrderPrice(chain Chain, o *OrderV2) (Amount, TokenInfo, error) {
+	t, err := r.Resolve(chain, o.PaymentTokenAddress().String())
+	if err != nil {
+		return o.CurrentPrice, TokenInfo{}, err
+	}
+	return o.CurrentPrice.WithDecimals(t.Decimals), t, nil
+}
+
+// EventPrice returns the total price, or offer amount, of e labelled with the decimals of its payment token,
+// for events whose payment token comes without decimals.
+func (r *TokenRegistry) EventPrice(chain Chain, e *Event) (Amount, TokenInfo, error) {
+	amount := e.TotalPrice
+	if amount.Int == nil {
+		amount = e.BidAmount
+	}
+	token := NullAddress.String()
+	if e.PaymentToken != nil {
+		switch {
+		case e.PaymentToken.Address != "":
+			token = e.PaymentToken.Address.String()
+		case e.PaymentToken.Symbol != "":
+			token = e.PaymentToken.Symbol
+		}
+	}
+	t, err := r.Resolve(chain, token)
+	if err != nil {
+		return amount, TokenInfo{}, err
+	}
+	return amount.WithDecimals(t.Decimals), t, nil
+}
+
+// PaymentTokenAddress is the currency of the first currency item of the consideration, ETH when there is none.
+func (o OrderV2) PaymentTokenAddress() Address {
+	if o.ProtocolData != nil && o.ProtocolData.Parameters != nil {
+		for _, v := range o.ProtocolData.Parameters.Consideration {
+			if v.ItemType == ItemNative || v.ItemType == ItemERC20 {
+				return v.Token
+			}
+		}
+	}
+	return NullAddress
+}
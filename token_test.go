@@ -0,0 +1,59 @@
+package opensea
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTokenRegistryResolve(t *testing.T) {
+	r := NewTokenRegistry()
+
+	usdc, err := r.Resolve(ChainEthereum, "usdc")
+	assert.Nil(t, err)
+	assert.Equal(t, Address(testUSDC), usdc.Address)
+	assert.Equal(t, 6, usdc.Decimals)
+
+	weth, err := r.Resolve(ChainEthereum, "0xC02aaA39b223FE8D0A0e5C4F27eAD9083C756Cc2")
+	assert.Nil(t, err)
+	assert.Equal(t, "WETH", weth.Symbol)
+
+	eth, err := r.Resolve(ChainEthereum, NullAddress.String())
+	assert.Nil(t, err)
+	assert.True(t, eth.IsNative())
+
+	polygonUSDC, err := r.Resolve(ChainPolygon, "USDC")
+	assert.Nil(t, err)
+	assert.NotEqual(t, usdc.Address, polygonUSDC.Address)
+
+	_, err = r.Resolve(ChainEthereum, "DOGE")
+	assert.NotNil(t, err)
+	_, err = r.Resolve(ChainRinkeby, testUSDC)
+	assert.NotNil(t, err)
+
+	r.Register(TokenInfo{Chain: ChainEthereum, Symbol: "DOGE", Address: "0x4206931337dc273a630d328da6441786bfad668f", Decimals: 8})
+	doge, err := r.Resolve(ChainEthereum, "doge")
+	assert.Nil(t, err)
+	a, err := doge.Units("1.5")
+	assert.Nil(t, err)
+	assert.Equal(t, "150000000", a.String())
+}
+
+func TestTokenRegistryPrices(t *testing.T) {
+	r := NewTokenRegistry()
+
+	ord := loadListing(t)
+	ord.ProtocolData.Parameters.Consideration[0].Token = testUSDC
+	ord.ProtocolData.Parameters.Consideration[0].ItemType = ItemERC20
+	ord.CurrentPrice, _ = ParseAmount("2500000000", EtherDecimals)
+	amount, token, err := r.OrderPrice(ChainEthereum, ord)
+	assert.Nil(t, err)
+	assert.Equal(t, "USDC", token.Symbol)
+	assert.Equal(t, "2500", amount.Units())
+
+	e := &Event{TotalPrice: Ether("0.000000000012"), PaymentToken: &PaymentToken{Symbol: "USDC"}}
+	amount, token, err = r.EventPrice(ChainEthereum, e)
+	assert.Nil(t, err)
+	assert.Equal(t, Address(testUSDC), token.Address)
+	assert.Equal(t, "12", amount.Units())
+}
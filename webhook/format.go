@@ -0,0 +1,271 @@
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jumpblock/go-opensea"
+)
+
+// Format is how a destination wants notifications: the Notification itself, or a message for a Discord
+// or Slack incoming webhook.
+type Format string
+
+const (
+	FormatJSON    Format = ""
+	FormatDiscord Format = "discord"
+	FormatSlack   Format = "slack"
+)
+
+// ProfileURL is where the buyer and seller links of messages point.
+var ProfileURL = "https://opensea.io/"
+
+// Render returns the body posted for n in format f.
+func Render(f Format, n *Notification) ([]byte, error) {
+	switch f {
+	case FormatJSON:
+		return json.Marshal(n)
+	case FormatDiscord:
+		return Discord(n)
+	case FormatSlack:
+		return Slack(n)
+	}
+	return nil, fmt.Errorf("webhook: unknown format %q", f)
+}
+
+type discordMessage struct {
+	Embeds []discordEmbed `json:"embeds"`
+}
+
+type discordEmbed struct {
+	Title     string         `json:"title"`
+	URL       string         `json:"url,omitempty"`
+	Color     int            `json:"color"`
+	Image     *discordImage  `json:"image,omitempty"`
+	Fields    []discordField `json:"fields,omitempty"`
+	Footer    *discordFooter `json:"footer,omitempty"`
+	Timestamp string         `json:"timestamp,omitempty"`
+}
+
+type discordImage struct {
+	URL string `json:"url"`
+}
+
+type discordField struct {
+	Name   string `json:"name"`
+	Value  string `json:"value"`
+	Inline bool   `json:"inline"`
+}
+
+type discordFooter struct {
+	Text string `json:"text"`
+}
+
+// Discord renders n as a message with one embed, for a Discord webhook URL.
+func Discord(n *Notification) ([]byte, error) {
+	e := discordEmbed{
+		Title: title(n),
+		URL:   n.Permalink,
+		Color: colors[n.Kind],
+	}
+	if n.ImageURL != "" {
+		e.Image = &discordImage{URL: n.ImageURL}
+	}
+	for _, f := range fields(n) {
+		value := f.value
+		if f.account != "" {
+			value = fmt.Sprintf("[%s](%s)", shortAddress(f.account), ProfileURL+f.account.String())
+		}
+		e.Fields = append(e.Fields, discordField{Name: f.name, Value: value, Inline: true})
+	}
+	if n.Collection != "" {
+		e.Footer = &discordFooter{Text: n.Collection}
+	}
+	if !n.Timestamp.IsZero() {
+		e.Timestamp = n.Timestamp.Time().Format(time.RFC3339)
+	}
+	return marshal(discordMessage{Embeds: []discordEmbed{e}})
+}
+
+type slackMessage struct {
+	Text   string       `json:"text"`
+	Blocks []slackBlock `json:"blocks"`
+}
+
+type slackBlock struct {
+	Type      string        `json:"type"`
+	Text      *slackText    `json:"text,omitempty"`
+	Fields    []slackText   `json:"fields,omitempty"`
+	Accessory *slackElement `json:"accessory,omitempty"`
+	Elements  []slackText   `json:"elements,omitempty"`
+}
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type slackElement struct {
+	Type     string `json:"type"`
+	ImageURL string `json:"image_url"`
+	AltText  string `json:"alt_text"`
+}
+
+// Slack renders n as Block Kit blocks, for a Slack incoming webhook URL. Text is the plain summary
+// shown in notifications.
+func Slack(n *Notification) ([]byte, error) {
+	summary := title(n)
+	if p := price(n); p != "" {
+		summary += " for " + p
+	}
+	head := slackBlock{Type: "section", Text: &slackText{Type: "mrkdwn", Text: slackEscape(title(n))}}
+	if n.Permalink != "" {
+		head.Text.Text = fmt.Sprintf("*<%s|%s>* %s", n.Permalink, slackEscape(name(n)), verb(n))
+	}
+	if n.ImageURL != "" {
+		head.Accessory = &slackElement{Type: "image", ImageURL: n.ImageURL, AltText: name(n)}
+	}
+	msg := slackMessage{Text: slackEscape(summary), Blocks: []slackBlock{head}}
+
+	var details []slackText
+	for _, f := range fields(n) {
+		value := slackEscape(f.value)
+		if f.account != "" {
+			value = fmt.Sprintf("<%s|%s>", ProfileURL+f.account.String(), shortAddress(f.account))
+		}
+		details = append(details, slackText{Type: "mrkdwn", Text: "*" + f.name + "*\n" + value})
+	}
+	if len(details) > 0 {
+		msg.Blocks = append(msg.Blocks, slackBlock{Type: "section", Fields: details})
+	}
+
+	var context []string
+	if n.Collection != "" {
+		context = append(context, slackEscape(n.Collection))
+	}
+	if !n.Timestamp.IsZero() {
+		t := n.Timestamp.Time()
+		context = append(context, fmt.Sprintf("<!date^%d^{date_short_pretty} {time}|%s>", t.Unix(), t.Format(time.RFC3339)))
+	}
+	if len(context) > 0 {
+		msg.Blocks = append(msg.Blocks, slackBlock{Type: "context", Elements: []slackText{{Type: "mrkdwn", Text: strings.Join(context, " · ")}}})
+	}
+	return marshal(msg)
+}
+
+// marshal leaves <, > and & as they are, which both services read fine and people read better.
+func marshal(v interface{}) ([]byte, error) {
+	var b bytes.Buffer
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, err
+	}
+	return bytes.TrimRight(b.Bytes(), "\n"), nil
+}
+
+var verbs = map[Kind]string{
+	KindSale:     "sold",
+	KindListing:  "listed",
+	KindTransfer: "transferred",
+	KindCancel:   "listing cancelled",
+	KindOffer:    "received an offer",
+}
+
+var colors = map[Kind]int{
+	KindSale:     0x2ecc71,
+	KindListing:  0x3498db,
+	KindTransfer: 0x95a5a6,
+	KindCancel:   0xe74c3c,
+	KindOffer:    0xf39c12,
+}
+
+type field struct {
+	name    string
+	value   string
+	account opensea.Address // rendered as a profile link instead of value
+}
+
+// fields are the details of a message, in the order they are shown.
+func fields(n *Notification) []field {
+	var fs []field
+	if p := price(n); p != "" {
+		fs = append(fs, field{name: "Price", value: p})
+	}
+	if n.Rank > 0 {
+		fs = append(fs, field{name: "Rarity", value: "#" + strconv.Itoa(n.Rank)})
+	}
+	from, to := "From", "To"
+	switch n.Kind {
+	case KindSale:
+		from, to = "Seller", "Buyer"
+	case KindListing, KindCancel:
+		from = "Seller"
+	case KindOffer:
+		from = "Bidder"
+	}
+	if n.From != "" && !n.From.IsNullAddress() {
+		fs = append(fs, field{name: from, account: n.From})
+	}
+	if n.To != "" && !n.To.IsNullAddress() {
+		fs = append(fs, field{name: to, account: n.To})
+	}
+	return fs
+}
+
+// price reads like "0.2 WETH ($540.12)", or "2.5 USDC (0.0019 ETH, $2.50)" for tokens other than ETH.
+func price(n *Notification) string {
+	if n.PriceUnits == "" {
+		return ""
+	}
+	s := n.PriceUnits
+	if n.Symbol != "" {
+		s += " " + n.Symbol
+	}
+	var values []string
+	if n.PriceEth != "" {
+		values = append(values, n.PriceEth+" ETH")
+	}
+	if n.PriceUsd != "" {
+		values = append(values, "$"+n.PriceUsd)
+	}
+	if len(values) > 0 {
+		s += " (" + strings.Join(values, ", ") + ")"
+	}
+	return s
+}
+
+func title(n *Notification) string {
+	return name(n) + " " + verb(n)
+}
+
+func verb(n *Notification) string {
+	if v, ok := verbs[n.Kind]; ok {
+		return v
+	}
+	return n.EventType
+}
+
+func name(n *Notification) string {
+	if n.Name != "" {
+		return n.Name
+	}
+	return "#" + n.TokenID
+}
+
+// shortAddress reads like 0xbc0c…3ec3.
+func shortAddress(a opensea.Address) string {
+	s := a.String()
+	if len(s) < 10 {
+		return s
+	}
+	return s[:6] + "…" + s[len(s)-4:]
+}
+
+func slackEscape(s string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(s)
+}
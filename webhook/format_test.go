@@ -0,0 +1,144 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/jumpblock/go-opensea"
+	"github.com/jumpblock/go-opensea/stream"
+	"github.com/stretchr/testify/assert"
+)
+
+var update = flag.Bool("update", false, "rewrite the golden files")
+
+// goldenSale is the WETH sale of the events fixture, with a rarity rank.
+func goldenSale(t *testing.T) *Notification {
+	b, err := ioutil.ReadFile("../test-files/opensea-events.json")
+	assert.Nil(t, err)
+	var resp opensea.AssetEventsResponse
+	assert.Nil(t, json.Unmarshal(b, &resp))
+	for _, e := range resp.AssetEvents {
+		if e.ID == 651665248 {
+			n := FromEvent(e)
+			n.Rank = 42
+			return n
+		}
+	}
+	t.Fatal("sale not in fixture")
+	return nil
+}
+
+func goldenListing() *Notification {
+	price, _ := opensea.ParseAmount("2500000", 6)
+	at, _ := opensea.ParseTime("2022-10-04T20:53:12.116405+00:00")
+	return FromStream(&stream.Event{
+		Type:       opensea.EventTypeItemListed,
+		Collection: "doodles-official",
+		Payload: &stream.ItemListed{
+			Item: stream.Item{
+				NftID:     "ethereum/0x8a90cab2b38dba80c64b7734e58ee1db38b8992e/1234",
+				Permalink: "https://opensea.io/assets/ethereum/0x8a90cab2b38dba80c64b7734e58ee1db38b8992e/1234",
+				Metadata:  stream.ItemMetadata{Name: "Doodle <#1234>", ImageURL: "https://i.seadn.io/gcs/files/doodle-1234.png"},
+			},
+			EventTimestamp: at,
+			BasePrice:      price,
+			Maker:          stream.Account{Address: "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed"},
+			PaymentToken:   stream.PaymentToken{Symbol: "USDC", Decimals: 6, EthPrice: "0.000754", UsdPrice: "1.000000000000000000"},
+			Quantity:       1,
+			OrderHash:      "0xabc",
+		},
+	})
+}
+
+func assertGolden(t *testing.T, name string, got []byte) {
+	path := filepath.Join("../test-files/golden", name)
+	if *update {
+		var out bytes.Buffer
+		assert.Nil(t, json.Indent(&out, got, "", "  "))
+		assert.Nil(t, ioutil.WriteFile(path, append(out.Bytes(), '\n'), 0o644))
+	}
+	want, err := ioutil.ReadFile(path)
+	assert.Nil(t, err)
+	assert.JSONEq(t, string(want), string(got))
+}
+
+func TestPrice(t *testing.T) {
+	usdc, _ := opensea.ParseAmount("2500000000", 6)
+	cases := []struct {
+		amount opensea.Amount
+		token  *opensea.PaymentToken
+		want   string
+		eth    string
+	}{
+		{usdc, &opensea.PaymentToken{Symbol: "USDC", EthPrice: "0.000754", UsdPrice: "1.0"}, "2500 USDC (1.885 ETH, $2500.00)", "1.885"},
+		{usdc, &opensea.PaymentToken{Symbol: "USDC", UsdPrice: "1.0"}, "2500 USDC ($2500.00)", ""},
+		{opensea.Ether("2"), &opensea.PaymentToken{Symbol: "APE", EthPrice: "0.002"}, "2 APE (0.004 ETH)", "0.004"},
+		{opensea.Ether("0.2"), &opensea.PaymentToken{Symbol: "WETH", EthPrice: "1", UsdPrice: "2700.6"}, "0.2 WETH ($540.12)", ""},
+		{opensea.Ether("0.2"), &opensea.PaymentToken{Symbol: "ETH", EthPrice: "1"}, "0.2 ETH", ""},
+		{opensea.Ether("0.2"), nil, "0.2", ""},
+	}
+	for _, c := range cases {
+		n := FromEvent(&opensea.Event{EventType: opensea.EventTypeItemSold, TotalPrice: c.amount, PaymentToken: c.token})
+		assert.Equal(t, c.want, price(n))
+		assert.Equal(t, c.eth, n.PriceEth)
+	}
+}
+
+func TestDiscord(t *testing.T) {
+	b, err := Discord(goldenSale(t))
+	assert.Nil(t, err)
+	assertGolden(t, "discord-sale.json", b)
+
+	b, err = Discord(goldenListing())
+	assert.Nil(t, err)
+	assertGolden(t, "discord-listing.json", b)
+}
+
+func TestSlack(t *testing.T) {
+	b, err := Slack(goldenSale(t))
+	assert.Nil(t, err)
+	assertGolden(t, "slack-sale.json", b)
+
+	b, err = Slack(goldenListing())
+	assert.Nil(t, err)
+	assertGolden(t, "slack-listing.json", b)
+}
+
+func TestDispatchFormats(t *testing.T) {
+	bodies := map[string][]byte{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := ioutil.ReadAll(r.Body)
+		bodies[r.URL.Path] = b
+	}))
+	defer srv.Close()
+
+	d := &Dispatcher{
+		Destinations: []Destination{
+			{URL: srv.URL + "/discord", Format: FormatDiscord},
+			{URL: srv.URL + "/slack", Format: FormatSlack, Filter: Filter{Kinds: []Kind{KindSale}}},
+		},
+		Rank: func(n *Notification) int { return 7 },
+	}
+	n := goldenListing()
+	assert.Nil(t, d.Dispatch(context.Background(), n))
+	assert.Contains(t, string(bodies["/discord"]), `"embeds"`)
+	assert.Contains(t, string(bodies["/discord"]), `"#7"`)
+	assert.NotContains(t, bodies, "/slack")
+	assert.Equal(t, 0, n.Rank, "the rank is set on a copy")
+
+	_, err := Render(Format("teams"), goldenListing())
+	assert.EqualError(t, err, `webhook: unknown format "teams"`)
+
+	// a body that cannot be rendered stops the dispatch before anything is sent
+	delete(bodies, "/discord")
+	d.Destinations = append(d.Destinations, Destination{URL: srv.URL + "/teams", Format: Format("teams")})
+	assert.EqualError(t, d.Dispatch(context.Background(), goldenListing()), `webhook: unknown format "teams"`)
+	assert.Empty(t, bodies)
+}
@@ -0,0 +1,209 @@
+package webhook
+
+import (
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/jumpblock/go-opensea"
+	"github.com/jumpblock/go-opensea/stream"
+)
+
+type Kind string
+
+const (
+	KindSale     Kind = "sale"
+	KindListing  Kind = "listing"
+	KindTransfer Kind = "transfer"
+	KindCancel   Kind = "cancel"
+	KindOffer    Kind = "offer"
+)
+
+// Notification is the JSON body posted to destinations, the same whether the event was polled
+// from the events endpoint or received from the Stream API. From is the seller, lister, offerer or
+// sender, To the buyer or receiver.
+type Notification struct {
+	ID          string           `json:"id"`
+	Kind        Kind             `json:"kind"`
+	Collection  string           `json:"collection"`
+	Chain       string           `json:"chain,omitempty"`
+	Contract    opensea.Address  `json:"contract"`
+	TokenID     string           `json:"token_id"`
+	Name        string           `json:"name,omitempty"`
+	ImageURL    string           `json:"image_url,omitempty"`
+	Permalink   string           `json:"permalink,omitempty"`
+	From        opensea.Address  `json:"from,omitempty"`
+	To          opensea.Address  `json:"to,omitempty"`
+	Price       *opensea.Amount  `json:"price,omitempty"`
+	PriceUnits  string           `json:"price_units,omitempty"`
+	Symbol      string           `json:"symbol,omitempty"`
+	PriceUsd    string           `json:"price_usd,omitempty"`
+	PriceEth    string           `json:"price_eth,omitempty"` // for payment tokens other than ETH and WETH
+	Rank        int              `json:"rank,omitempty"`      // rarity rank of the token, when the Dispatcher knows it
+	Quantity    string           `json:"quantity,omitempty"`
+	Transaction string           `json:"transaction,omitempty"`
+	Timestamp   opensea.TimeNano `json:"timestamp"`
+	EventType   string           `json:"event_type"`
+}
+
+// FromEvent converts an event of any of the events APIs, as delivered by an EventTailer. Its kind is
+// that of opensea.Event.Kind, item, collection and trait offers all being KindOffer.
+func FromEvent(e *opensea.Event) *Notification {
+	n := &Notification{
+		ID:         "event-" + strconv.FormatUint(e.ID, 10),
+		Kind:       Kind(e.Kind().String()),
+		Collection: e.CollectionSlug,
+		Contract:   e.ContractAddress,
+		Quantity:   e.Quantity,
+		Timestamp:  opensea.TimeNano(e.OccurredAt()),
+		EventType:  string(e.EventType),
+	}
+	if e.Kind() == opensea.EventKindUnknown {
+		n.Kind = Kind(e.EventType)
+	}
+	if a := e.Asset; a != nil {
+		n.TokenID, n.Name, n.ImageURL, n.Permalink = a.TokenID, a.Name, a.ImageURL, a.Permalink
+		// the contract of a sale is the exchange's, the asset's is the token's
+		if a.AssetContract != nil {
+			n.Contract = a.AssetContract.Address
+		}
+		if a.Collection != nil && n.Collection == "" {
+			n.Collection = a.Collection.Slug
+		}
+	}
+	if e.Transaction != nil {
+		n.Transaction = e.Transaction.TransactionHash
+	}
+	switch p := e.Payload().(type) {
+	case *opensea.SalePayload:
+		n.From, n.To = address(p.Seller), address(p.Buyer)
+		n.setPrice(p.TotalPrice, p.PaymentToken)
+	case *opensea.ListingPayload:
+		n.From = address(p.Seller)
+		n.setPrice(p.StartingPrice, p.PaymentToken)
+	case *opensea.TransferPayload:
+		n.From, n.To = address(p.From), address(p.To)
+	case *opensea.CancelPayload:
+		n.From = address(p.Maker)
+	case *opensea.OfferPayload:
+		n.Kind = KindOffer
+		n.From = address(p.Maker)
+		n.setPrice(p.Amount, p.PaymentToken)
+	}
+	return n
+}
+
+// FromStream converts an event of the Stream API. The id is the order hash, or the transaction hash
+// for transfers, so that an event received twice gets the same id.
+func FromStream(e *stream.Event) *Notification {
+	n := &Notification{
+		Kind:       Kind(e.Type),
+		Collection: e.Collection,
+		Timestamp:  e.SentAt,
+		EventType:  string(e.Type),
+	}
+	var item *stream.Item
+	switch p := e.Payload.(type) {
+	case *stream.ItemListed:
+		n.Kind, item = KindListing, &p.Item
+		n.ID, n.From, n.Timestamp = p.OrderHash, p.Maker.Address, p.EventTimestamp
+		n.Quantity = strconv.FormatInt(p.Quantity, 10)
+		n.setStreamPrice(p.BasePrice, p.PaymentToken)
+	case *stream.ItemSold:
+		n.Kind, item = KindSale, &p.Item
+		n.ID, n.From, n.To, n.Timestamp = p.OrderHash, p.Maker.Address, p.Taker.Address, p.EventTimestamp
+		n.Quantity, n.Transaction = strconv.FormatInt(p.Quantity, 10), p.Transaction.Hash
+		n.setStreamPrice(p.SalePrice, p.PaymentToken)
+	case *stream.ItemTransferred:
+		n.Kind, item = KindTransfer, &p.Item
+		n.ID, n.From, n.To, n.Timestamp = p.Transaction.Hash, p.FromAccount.Address, p.ToAccount.Address, p.EventTimestamp
+		n.Quantity, n.Transaction = strconv.FormatInt(p.Quantity, 10), p.Transaction.Hash
+	case *stream.ItemCancelled:
+		n.Kind, item = KindCancel, &p.Item
+		n.ID, n.Timestamp = p.OrderHash, p.EventTimestamp
+		n.Quantity, n.Transaction = strconv.FormatInt(p.Quantity, 10), p.Transaction.Hash
+	case *stream.Offer:
+		n.Kind, item = KindOffer, p.Item
+		n.ID, n.From, n.Timestamp = p.OrderHash, p.Maker.Address, p.EventTimestamp
+		n.Quantity = strconv.FormatInt(p.Quantity, 10)
+		n.setStreamPrice(p.BasePrice, p.PaymentToken)
+	case *stream.ItemMetadataUpdated:
+		item = &p.Item
+	}
+	if item != nil {
+		n.Name, n.ImageURL, n.Permalink = item.Metadata.Name, item.Metadata.ImageURL, item.Permalink
+		// nft_id reads chain/contract/token_id
+		if parts := strings.SplitN(item.NftID, "/", 3); len(parts) == 3 {
+			n.Chain, n.TokenID = parts[0], parts[2]
+			n.Contract, _ = opensea.ParseAddress(parts[1])
+		}
+		if n.Chain == "" {
+			n.Chain = item.Chain.Name
+		}
+	}
+	if n.ID != "" {
+		n.ID = "stream-" + string(n.Kind) + "-" + n.ID
+	}
+	return n
+}
+
+func (n *Notification) setPrice(a opensea.Amount, token *opensea.PaymentToken) {
+	if a.Int == nil {
+		return
+	}
+	n.Price, n.PriceUnits = &a, a.Units()
+	if token != nil {
+		n.Symbol, n.PriceUsd = token.Symbol, usd(a, token.UsdPrice)
+		n.PriceEth = eth(a, token.Symbol, token.EthPrice)
+	}
+}
+
+func (n *Notification) setStreamPrice(a opensea.Amount, token stream.PaymentToken) {
+	if a.Int == nil {
+		return
+	}
+	n.Price, n.PriceUnits, n.Symbol, n.PriceUsd = &a, a.Units(), token.Symbol, usd(a, token.UsdPrice)
+	n.PriceEth = eth(a, token.Symbol, token.EthPrice)
+}
+
+// usd is the value of a in dollars, to the cent, or "" when the token price is unknown.
+func usd(a opensea.Amount, price opensea.Decimal) string {
+	v := value(a, price)
+	if v == nil {
+		return ""
+	}
+	return v.FloatString(2)
+}
+
+// eth is the value of a in ETH, to 4 decimals without trailing zeros, or "" when the token is ETH
+// or WETH, whose price is the amount itself, or its price is unknown.
+func eth(a opensea.Amount, symbol string, price opensea.Decimal) string {
+	if sameToken(symbol, "ETH") {
+		return ""
+	}
+	v := value(a, price)
+	if v == nil {
+		return ""
+	}
+	s := strings.TrimRight(v.FloatString(4), "0")
+	return strings.TrimSuffix(s, ".")
+}
+
+func value(a opensea.Amount, price opensea.Decimal) *big.Rat {
+	r := price.Rat()
+	if r == nil {
+		return nil
+	}
+	v, ok := new(big.Rat).SetString(a.Units())
+	if !ok {
+		return nil
+	}
+	return v.Mul(v, r)
+}
+
+func address(a *opensea.Account) opensea.Address {
+	if a == nil {
+		return ""
+	}
+	return a.Address
+}
@@ -0,0 +1,96 @@
+package webhook
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+
+	"github.com/jumpblock/go-opensea"
+	"github.com/jumpblock/go-opensea/stream"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFromEvent(t *testing.T) {
+	b, err := ioutil.ReadFile("../test-files/opensea-events.json")
+	assert.Nil(t, err)
+	var resp opensea.AssetEventsResponse
+	assert.Nil(t, json.Unmarshal(b, &resp))
+
+	var sale *opensea.Event
+	for _, e := range resp.AssetEvents {
+		if e.ID == 651665248 {
+			sale = e
+		}
+	}
+	n := FromEvent(sale)
+	assert.Equal(t, "event-651665248", n.ID)
+	assert.Equal(t, KindSale, n.Kind)
+	assert.Equal(t, "forgotten-runes-mafriends", n.Collection)
+	assert.Equal(t, opensea.Address("0xbc0c08bc6c8e949d54db31eddc8ee809ef323ec3"), n.From)
+	assert.Equal(t, opensea.Address("0x45aef14112f92bdc3fa08ca0d85c8a96af47c490"), n.To)
+	assert.Equal(t, "0.2", n.PriceUnits)
+	assert.Equal(t, "WETH", n.Symbol)
+	assert.Equal(t, "0xf2a9c30f322f9c6588e394c3efc2ccdb0220dff07a7b8742f1f8e6a9f2e75baf", n.Transaction)
+	assert.False(t, n.Timestamp.IsZero())
+}
+
+func TestFromEventNewerTypes(t *testing.T) {
+	asset := &opensea.Asset{TokenID: "1234", AssetContract: &opensea.AssetContract{Address: "0x8a90cab2b38dba80c64b7734e58ee1db38b8992e"}}
+	seller := &opensea.Account{Address: "0xbc0c08bc6c8e949d54db31eddc8ee809ef323ec3"}
+	buyer := &opensea.Account{Address: "0x45aef14112f92bdc3fa08ca0d85c8a96af47c490"}
+	weth := &opensea.PaymentToken{Symbol: "WETH", Decimals: 18}
+	min := opensea.Ether("0.5")
+	sales := Filter{Kinds: []Kind{KindSale}, MinPrice: &min}
+
+	for _, typ := range []opensea.EventType{opensea.EventTypeSale, opensea.EventTypeItemSold} {
+		n := FromEvent(&opensea.Event{EventType: typ, Asset: asset, Seller: seller, Buyer: buyer, TotalPrice: opensea.Ether("1"), PaymentToken: weth})
+		assert.Equal(t, KindSale, n.Kind, typ)
+		assert.Equal(t, seller.Address, n.From, typ)
+		assert.Equal(t, buyer.Address, n.To, typ)
+		assert.Equal(t, "1", n.PriceUnits, typ)
+		assert.Equal(t, string(typ), n.EventType)
+		assert.True(t, sales.Match(n), typ)
+	}
+	for _, typ := range []opensea.EventType{opensea.EventTypeTransfer, opensea.EventTypeItemTransferred} {
+		n := FromEvent(&opensea.Event{EventType: typ, Asset: asset, FromAccount: seller, ToAccount: buyer})
+		assert.Equal(t, KindTransfer, n.Kind, typ)
+		assert.Equal(t, seller.Address, n.From, typ)
+		assert.Equal(t, buyer.Address, n.To, typ)
+	}
+	for _, typ := range []opensea.EventType{opensea.EventTypeCancel, opensea.EventTypeItemCancelled} {
+		n := FromEvent(&opensea.Event{EventType: typ, Asset: asset, Maker: seller})
+		assert.Equal(t, KindCancel, n.Kind, typ)
+		assert.Equal(t, seller.Address, n.From, typ)
+	}
+	n := FromEvent(&opensea.Event{EventType: opensea.EventTypeCollectionOffer, Maker: buyer, BidAmount: opensea.Ether("0.6"), PaymentToken: weth})
+	assert.Equal(t, KindOffer, n.Kind)
+	assert.Equal(t, "0.6", n.PriceUnits)
+	n = FromEvent(&opensea.Event{EventType: "something_new"})
+	assert.Equal(t, Kind("something_new"), n.Kind)
+}
+
+func TestFromStream(t *testing.T) {
+	price, _ := opensea.ParseAmount("2500000", 6)
+	e := &stream.Event{
+		Type:       opensea.EventTypeItemListed,
+		Collection: "doodles-official",
+		Payload: &stream.ItemListed{
+			Item:         stream.Item{NftID: "ethereum/0x8a90cab2b38dba80c64b7734e58ee1db38b8992e/1234", Metadata: stream.ItemMetadata{Name: "Doodle #1234"}},
+			BasePrice:    price,
+			Maker:        stream.Account{Address: "0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed"},
+			PaymentToken: stream.PaymentToken{Symbol: "USDC", Decimals: 6},
+			Quantity:     1,
+			OrderHash:    "0xabc",
+		},
+	}
+	n := FromStream(e)
+	assert.Equal(t, "stream-listing-0xabc", n.ID)
+	assert.Equal(t, KindListing, n.Kind)
+	assert.Equal(t, "ethereum", n.Chain)
+	assert.Equal(t, opensea.Address("0x8a90cab2b38dba80c64b7734e58ee1db38b8992e"), n.Contract)
+	assert.Equal(t, "1234", n.TokenID)
+	assert.Equal(t, "Doodle #1234", n.Name)
+	assert.Equal(t, "2.5", n.PriceUnits)
+	assert.Equal(t, "USDC", n.Symbol)
+	assert.Equal(t, opensea.Address("0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed"), n.From)
+}
@@ -0,0 +1,336 @@
+// Package webhook posts OpenSea events, polled by an EventTailer or received from the Stream API,
+// to HTTP endpoints as signed JSON, or as Discord and Slack messages.
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/jumpblock/go-opensea"
+	"github.com/jumpblock/go-opensea/stream"
+)
+
+const (
+	SignatureHeader = "X-Opensea-Webhook-Signature"
+	TimestampHeader = "X-Opensea-Webhook-Timestamp"
+	IDHeader        = "X-Opensea-Webhook-Id"
+)
+
+// Filter selects the notifications sent to a destination. Empty fields match everything.
+type Filter struct {
+	Collections []string
+	Kinds       []Kind
+	// MinPrice is compared with the prices paid in the token of symbol MinPriceSymbol, e.g. opensea.Ether("0.5");
+	// notifications without a price, or priced in another token, do not pass it.
+	MinPrice *opensea.Amount
+	// MinPriceSymbol is the token of MinPrice, ETH if empty. ETH and WETH are the same token here.
+	MinPriceSymbol string
+	// Accounts matches notifications whose From or To is one of them.
+	Accounts []opensea.Address
+}
+
+func (f Filter) Match(n *Notification) bool {
+	if len(f.Collections) > 0 && !containsString(f.Collections, n.Collection) {
+		return false
+	}
+	if len(f.Kinds) > 0 {
+		found := false
+		for _, k := range f.Kinds {
+			found = found || k == n.Kind
+		}
+		if !found {
+			return false
+		}
+	}
+	if f.MinPrice != nil && (n.Price == nil || !sameToken(f.MinPriceSymbol, n.Symbol) || n.Price.Cmp(*f.MinPrice) < 0) {
+		return false
+	}
+	if len(f.Accounts) > 0 {
+		found := false
+		for _, a := range f.Accounts {
+			found = found || (a != "" && (strings.EqualFold(string(a), string(n.From)) || strings.EqualFold(string(a), string(n.To))))
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// sameToken reports whether two payment token symbols name the same token, "" being ETH.
+func sameToken(a, b string) bool {
+	norm := func(s string) string {
+		if s = strings.ToUpper(s); s == "" || s == "WETH" {
+			return "ETH"
+		}
+		return s
+	}
+	return norm(a) == norm(b)
+}
+
+// Destination is an endpoint receiving the notifications its Filter matches. When Secret is set the
+// requests carry an HMAC-SHA256 signature, see Sign. Discord and Slack incoming webhook URLs take
+// FormatDiscord and FormatSlack.
+type Destination struct {
+	Name    string
+	URL     string
+	Secret  string
+	Headers map[string]string
+	Filter  Filter
+	Format  Format
+}
+
+// Dispatcher delivers notifications to its destinations, retrying failed deliveries with backoff and
+// appending the ones that still fail to the DeadLetter file.
+type Dispatcher struct {
+	Destinations []Destination
+	HTTPClient   *http.Client
+	MaxAttempts  int           // per delivery, 5 if zero
+	Backoff      time.Duration // before the first retry, doubled up to MaxBackoff; 1s if zero
+	MaxBackoff   time.Duration // 1 minute if zero
+	DeadLetter   string        // path of a JSON lines file, deliveries are dropped if empty
+	// OnError is told of each failed attempt.
+	OnError func(d Destination, n *Notification, err error)
+	// Rank looks up the rarity rank of the token of a notification, for instance in the scores of
+	// rarity.Rank; 0 is unknown.
+	Rank func(n *Notification) int
+
+	mu sync.Mutex
+}
+
+// DeadLetter is a line of the dead-letter file.
+type DeadLetter struct {
+	Destination  string        `json:"destination"`
+	URL          string        `json:"url"`
+	Notification *Notification `json:"notification"`
+	Error        string        `json:"error"`
+	Attempts     int           `json:"attempts"`
+	FailedAt     time.Time     `json:"failed_at"`
+}
+
+// Dispatch sends n to every matching destination at once and returns when all deliveries succeeded
+// or were dead-lettered. It fails only when a dead letter could not be written or ctx is done.
+func (d *Dispatcher) Dispatch(ctx context.Context, n *Notification) error {
+	if d.Rank != nil && n.Rank == 0 {
+		ranked := *n
+		ranked.Rank = d.Rank(n)
+		n = &ranked
+	}
+	// every body is rendered before any delivery starts, so that a rendering error sends nothing
+	bodies := make([][]byte, len(d.Destinations))
+	byFormat := map[Format][]byte{}
+	for i, dst := range d.Destinations {
+		if !dst.Filter.Match(n) {
+			continue
+		}
+		body, ok := byFormat[dst.Format]
+		if !ok {
+			var err error
+			if body, err = Render(dst.Format, n); err != nil {
+				return err
+			}
+			byFormat[dst.Format] = body
+		}
+		bodies[i] = body
+	}
+	var wg sync.WaitGroup
+	errs := make([]error, len(d.Destinations))
+	for i, dst := range d.Destinations {
+		if bodies[i] == nil {
+			continue
+		}
+		wg.Add(1)
+		go func(i int, dst Destination) {
+			defer wg.Done()
+			errs[i] = d.deliver(ctx, dst, n, bodies[i])
+		}(i, dst)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return ctx.Err()
+}
+
+// EventHandler adapts the dispatcher to an EventTailer, whose handler it can be.
+func (d *Dispatcher) EventHandler() func(ctx context.Context, e *opensea.Event) error {
+	return func(ctx context.Context, e *opensea.Event) error {
+		return d.Dispatch(ctx, FromEvent(e))
+	}
+}
+
+// StreamHandler adapts the dispatcher to a stream subscription. Errors go to OnError with an empty destination.
+func (d *Dispatcher) StreamHandler(ctx context.Context) func(*stream.Event) {
+	return func(e *stream.Event) {
+		n := FromStream(e)
+		if err := d.Dispatch(ctx, n); err != nil && d.OnError != nil {
+			d.OnError(Destination{}, n, err)
+		}
+	}
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, dst Destination, n *Notification, body []byte) error {
+	attempts := d.MaxAttempts
+	if attempts <= 0 {
+		attempts = 5
+	}
+	backoff := d.Backoff
+	if backoff <= 0 {
+		backoff = time.Second
+	}
+	maxBackoff := d.MaxBackoff
+	if maxBackoff <= 0 {
+		maxBackoff = time.Minute
+	}
+
+	var err error
+	attempt := 0
+	for attempt < attempts {
+		attempt++
+		var retry bool
+		var wait time.Duration
+		retry, wait, err = d.post(ctx, dst, n, body)
+		if err == nil {
+			return nil
+		}
+		if d.OnError != nil {
+			d.OnError(dst, n, err)
+		}
+		if !retry || attempt == attempts {
+			break
+		}
+		if wait < backoff {
+			wait = backoff
+		}
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+		backoff *= 2
+		if backoff > maxBackoff {
+			backoff = maxBackoff
+		}
+	}
+	if ctx.Err() != nil {
+		return ctx.Err()
+	}
+	return d.deadLetter(DeadLetter{
+		Destination:  dst.Name,
+		URL:          dst.URL,
+		Notification: n,
+		Error:        err.Error(),
+		Attempts:     attempt,
+		FailedAt:     time.Now().UTC(),
+	})
+}
+
+// post makes one attempt, telling whether a failure is worth retrying and how long the endpoint
+// asked to wait.
+func (d *Dispatcher) post(ctx context.Context, dst Destination, n *Notification, body []byte) (bool, time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, dst.URL, bytes.NewReader(body))
+	if err != nil {
+		return false, 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(IDHeader, n.ID)
+	for k, v := range dst.Headers {
+		req.Header.Set(k, v)
+	}
+	if dst.Secret != "" {
+		ts := strconv.FormatInt(time.Now().Unix(), 10)
+		req.Header.Set(TimestampHeader, ts)
+		req.Header.Set(SignatureHeader, Sign(dst.Secret, ts, body))
+	}
+
+	client := d.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return true, 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(ioutil.Discard, io.LimitReader(resp.Body, 1<<16))
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		return false, 0, nil
+	}
+	err = fmt.Errorf("webhook %s: %s", dst.URL, resp.Status)
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode >= 500:
+		secs, _ := strconv.Atoi(resp.Header.Get("Retry-After"))
+		return true, time.Duration(secs) * time.Second, err
+	case resp.StatusCode == http.StatusRequestTimeout:
+		return true, 0, err
+	}
+	return false, 0, err
+}
+
+func (d *Dispatcher) deadLetter(l DeadLetter) error {
+	if d.DeadLetter == "" {
+		return nil
+	}
+	b, err := json.Marshal(l)
+	if err != nil {
+		return err
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	f, err := os.OpenFile(d.DeadLetter, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	_, err = f.Write(append(b, '\n'))
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// Sign returns the signature header of a request: "sha256=" and the hex HMAC-SHA256, keyed by
+// secret, of the timestamp header, a dot and the body.
+func Sign(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte{'.'})
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature of a received request, for receivers written in Go. Requests older than
+// maxAge are refused, to prevent replays; zero disables the check.
+func Verify(secret string, r *http.Request, body []byte, maxAge time.Duration) bool {
+	ts := r.Header.Get(TimestampHeader)
+	if maxAge > 0 {
+		secs, err := strconv.ParseInt(ts, 10, 64)
+		if err != nil || time.Since(time.Unix(secs, 0)) > maxAge {
+			return false
+		}
+	}
+	return hmac.Equal([]byte(r.Header.Get(SignatureHeader)), []byte(Sign(secret, ts, body)))
+}
+
+func containsString(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
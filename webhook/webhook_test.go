@@ -0,0 +1,158 @@
+package webhook
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/jumpblock/go-opensea"
+	"github.com/stretchr/testify/assert"
+)
+
+func sale(price string) *Notification {
+	a := opensea.Ether(price)
+	return &Notification{
+		ID:         "event-1",
+		Kind:       KindSale,
+		Collection: "doodles-official",
+		From:       "0xbc0c08bc6c8e949d54db31eddc8ee809ef323ec3",
+		To:         "0x45aef14112f92bdc3fa08ca0d85c8a96af47c490",
+		Price:      &a,
+	}
+}
+
+func TestFilter(t *testing.T) {
+	min := opensea.Ether("0.5")
+	n := sale("0.75")
+
+	assert.True(t, Filter{}.Match(n))
+	assert.True(t, Filter{Collections: []string{"doodles-official"}, Kinds: []Kind{KindSale, KindListing}}.Match(n))
+	assert.False(t, Filter{Collections: []string{"azuki"}}.Match(n))
+	assert.False(t, Filter{Kinds: []Kind{KindTransfer}}.Match(n))
+	assert.True(t, Filter{MinPrice: &min}.Match(n))
+	assert.False(t, Filter{MinPrice: &min}.Match(sale("0.25")))
+	assert.False(t, Filter{MinPrice: &min}.Match(&Notification{Kind: KindTransfer}))
+
+	// prices in other tokens do not compare with MinPrice
+	usdc, _ := opensea.ParseUnits("1", 6)
+	n = sale("0")
+	n.Price, n.Symbol = &usdc, "USDC"
+	assert.False(t, Filter{MinPrice: &min}.Match(n))
+	usdcMin, _ := opensea.ParseUnits("0.5", 6)
+	assert.True(t, Filter{MinPrice: &usdcMin, MinPriceSymbol: "usdc"}.Match(n))
+	n = sale("0.75")
+	n.Symbol = "WETH"
+	assert.True(t, Filter{MinPrice: &min}.Match(n))
+	assert.True(t, Filter{Accounts: []opensea.Address{"0x45AEF14112F92BDC3FA08CA0D85C8A96AF47C490"}}.Match(n))
+	assert.False(t, Filter{Accounts: []opensea.Address{"0x5aaeb6053f3e94c9b9a09f33669435e7ef1beaed"}}.Match(n))
+}
+
+func TestDispatchSignsAndFilters(t *testing.T) {
+	var received int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := ioutil.ReadAll(r.Body)
+		if !Verify("s3cret", r, body, time.Minute) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		var n Notification
+		json.Unmarshal(body, &n)
+		assert.Equal(t, "event-1", n.ID)
+		assert.Equal(t, "0.75", n.Price.Units())
+		assert.Equal(t, "event-1", r.Header.Get(IDHeader))
+		atomic.AddInt32(&received, 1)
+	}))
+	defer srv.Close()
+
+	d := &Dispatcher{Destinations: []Destination{
+		{Name: "sales", URL: srv.URL, Secret: "s3cret", Filter: Filter{Kinds: []Kind{KindSale}}},
+		{Name: "transfers", URL: srv.URL, Secret: "s3cret", Filter: Filter{Kinds: []Kind{KindTransfer}}},
+	}}
+	assert.Nil(t, d.Dispatch(context.Background(), sale("0.75")))
+	assert.Equal(t, int32(1), atomic.LoadInt32(&received))
+}
+
+func TestVerify(t *testing.T) {
+	body := []byte(`{"id":"event-1"}`)
+	r := httptest.NewRequest(http.MethodPost, "/", nil)
+	ts := "1650110400"
+	r.Header.Set(TimestampHeader, ts)
+	r.Header.Set(SignatureHeader, Sign("s3cret", ts, body))
+
+	assert.True(t, Verify("s3cret", r, body, 0))
+	assert.False(t, Verify("other", r, body, 0))
+	assert.False(t, Verify("s3cret", r, []byte(`{"id":"event-2"}`), 0))
+	assert.False(t, Verify("s3cret", r, body, time.Minute))
+}
+
+func TestDispatchRetries(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer srv.Close()
+
+	dead := filepath.Join(t.TempDir(), "dead.jsonl")
+	var failures int32
+	d := &Dispatcher{
+		Destinations: []Destination{{Name: "sales", URL: srv.URL}},
+		Backoff:      time.Millisecond,
+		DeadLetter:   dead,
+		OnError:      func(Destination, *Notification, error) { atomic.AddInt32(&failures, 1) },
+	}
+	assert.Nil(t, d.Dispatch(context.Background(), sale("1")))
+	assert.Equal(t, int32(3), atomic.LoadInt32(&calls))
+	assert.Equal(t, int32(2), atomic.LoadInt32(&failures))
+	_, err := os.Stat(dead)
+	assert.True(t, os.IsNotExist(err))
+}
+
+func TestDispatchDeadLetters(t *testing.T) {
+	var calls int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&calls, 1)
+		if r.URL.Path == "/gone" {
+			w.WriteHeader(http.StatusGone)
+			return
+		}
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	dead := filepath.Join(t.TempDir(), "dead.jsonl")
+	d := &Dispatcher{
+		Destinations: []Destination{{Name: "down", URL: srv.URL + "/down"}, {Name: "gone", URL: srv.URL + "/gone"}},
+		MaxAttempts:  3,
+		Backoff:      time.Millisecond,
+		DeadLetter:   dead,
+	}
+	assert.Nil(t, d.Dispatch(context.Background(), sale("1")))
+	// 3 attempts for the unavailable endpoint, 1 for the one that will never accept it
+	assert.Equal(t, int32(4), atomic.LoadInt32(&calls))
+
+	f, err := os.Open(dead)
+	assert.Nil(t, err)
+	defer f.Close()
+	letters := map[string]DeadLetter{}
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		var l DeadLetter
+		assert.Nil(t, json.Unmarshal(scanner.Bytes(), &l))
+		letters[l.Destination] = l
+	}
+	assert.Len(t, letters, 2)
+	assert.Equal(t, 3, letters["down"].Attempts)
+	assert.Equal(t, 1, letters["gone"].Attempts)
+	assert.Equal(t, "event-1", letters["gone"].Notification.ID)
+	assert.Contains(t, letters["gone"].Error, "410")
+}